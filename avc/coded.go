@@ -0,0 +1,22 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// MacroBlockSize is the width and height of an AVC macroblock in luma samples.
+const MacroBlockSize = 16
+
+// Return the coded size of each picture, padded to whole macroblocks.
+// Field coded video has pictures of half frame height, e.g. 1080i is coded as 1920x544 fields.
+func CodedSize(width, height uint32, scanType video.ScanType) (codedWidth, codedHeight uint32) {
+	pictureHeight := height
+	if scanType.IsFieldCoded() {
+		pictureHeight = (pictureHeight + 1) / 2
+	}
+	return video.Mod16.Pad(width), video.Mod16.Pad(pictureHeight)
+}
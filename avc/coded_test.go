@@ -0,0 +1,41 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestCodedSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      uint32
+		height     uint32
+		scanType   video.ScanType
+		wantWidth  uint32
+		wantHeight uint32
+	}{
+		{"1080p", 1920, 1080, video.Progressive, 1920, 1088},
+		{"1080i", 1920, 1080, video.Interlaced, 1920, 544},
+		{"1080psf", 1920, 1080, video.FakeInterlaced, 1920, 544},
+		{"1080 telecined", 1920, 1080, video.Telecined, 1920, 1088},
+		{"480i", 720, 480, video.Interlaced, 720, 240},
+		{"odd height field", 720, 487, video.Interlaced, 720, 256},
+		{"single pixel", 1, 1, video.Progressive, 16, 16},
+		{"aligned", 3840, 2160, video.Progressive, 3840, 2160},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := CodedSize(tt.width, tt.height, tt.scanType)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("CodedSize(%d, %d, %v) = %dx%d, want %dx%d", tt.width, tt.height, tt.scanType, width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// Return the lowest AVC profile supporting specified bit depth and chroma format.
+// High 4:4:4 Predictive is also used for more than 10-bit, which High 10 and High 4:2:2 do not support.
+func ProfileName(bitDepth uint8, chromaFormat video.ChromaFormat) string {
+	if chromaFormat == video.Chroma444 || bitDepth > 10 {
+		return "High444"
+	}
+	if chromaFormat == video.Chroma422 {
+		return "High422"
+	}
+	if bitDepth > 8 {
+		return "High10"
+	}
+	return "High"
+}
+
+// Return the AVC profile of the EncodeProfile. Lossless coding requires High 4:4:4 Predictive,
+// and proxy is always 8-bit 4:2:0 in Main profile.
+func (p *EncodeProfile) ProfileName() string {
+	if p.Lossless {
+		return "High444"
+	}
+	if p.Proxy {
+		return "Main"
+	}
+	return ProfileName(p.OutputBitDepth(), p.ChromaFormat)
+}
+
+// Return the multiplier of level maximum bitrate for the AVC profile (cpbBrVclFactor / 1000).
+func BitRateMultiplier(profileName string) float64 {
+	switch profileName {
+	case "High":
+		return 1.25
+	case "High10":
+		return 3
+	case "High422", "High444":
+		return 4
+	}
+	return 1
+}
+
+// Return the maximum bitrate in kbps of the level for the AVC profile.
+func (p *AVCProfile) MaxBitRate(profileName string) uint32 {
+	return uint32(float64(p.BitRateKBMax) * BitRateMultiplier(profileName))
+}
+
+// Return the maximum coded picture buffer size in kbits of the level for the AVC profile.
+func (p *AVCProfile) MaxCPBSize(profileName string) uint32 {
+	return uint32(float64(p.CPBSizeKBMax) * BitRateMultiplier(profileName))
+}
+
+// Return the lowest level from specified level whose maximum bitrate for the AVC profile
+// is at least bitRate in kbps, or 0 if no level fits.
+func MinLevelByBitRate(level uint8, bitRate uint64, profileName string) uint8 {
+	for _, profile := range profiles {
+		if profile.Level >= level && uint64(profile.MaxBitRate(profileName)) >= bitRate {
+			return profile.Level
+		}
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestProfileName(t *testing.T) {
+	tests := []struct {
+		bitDepth     uint8
+		chromaFormat video.ChromaFormat
+		want         string
+	}{
+		{8, video.Chroma420, "High"},
+		{0, video.Chroma420, "High"},
+		{10, video.Chroma420, "High10"},
+		{8, video.Chroma422, "High422"},
+		{10, video.Chroma422, "High422"},
+		{12, video.Chroma420, "High444"},
+		{12, video.Chroma422, "High444"},
+		{8, video.Chroma444, "High444"},
+		{10, video.Chroma444, "High444"},
+	}
+	for _, tt := range tests {
+		if got := ProfileName(tt.bitDepth, tt.chromaFormat); got != tt.want {
+			t.Errorf("ProfileName(%d, %s) = %q, want %q", tt.bitDepth, tt.chromaFormat.Label(), got, tt.want)
+		}
+	}
+}
+
+func TestMaxBitRate(t *testing.T) {
+	tests := []struct {
+		profileName    string
+		wantMultiplier float64
+		wantBitRate    uint32
+	}{
+		{"Main", 1, 50000},
+		{"High", 1.25, 62500},
+		{"High10", 3, 150000},
+		{"High422", 4, 200000},
+		{"High444", 4, 200000},
+	}
+	level41 := ProfileByLevel(41)
+	for _, tt := range tests {
+		if got := BitRateMultiplier(tt.profileName); got != tt.wantMultiplier {
+			t.Errorf("BitRateMultiplier(%q) = %v, want %v", tt.profileName, got, tt.wantMultiplier)
+		}
+		if got := level41.MaxBitRate(tt.profileName); got != tt.wantBitRate {
+			t.Errorf("MaxBitRate(%q) = %d, want %d", tt.profileName, got, tt.wantBitRate)
+		}
+	}
+}
+
+func TestEncodeProfileName(t *testing.T) {
+	tests := []struct {
+		name         string
+		bitDepth     uint8
+		chromaFormat video.ChromaFormat
+		lossless     bool
+		proxy        bool
+		want         string
+	}{
+		{"8-bit", 8, video.Chroma420, false, false, "High"},
+		{"10-bit 4:2:2", 10, video.Chroma422, false, false, "High422"},
+		{"lossless 8-bit", 8, video.Chroma420, true, false, "High444"},
+		{"lossless 10-bit 4:2:2", 10, video.Chroma422, true, false, "High444"},
+		{"proxy", 8, video.Chroma420, false, true, "Main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &EncodeProfile{BitDepth: tt.bitDepth, ChromaFormat: tt.chromaFormat, Lossless: tt.lossless, Proxy: tt.proxy}
+			if got := profile.ProfileName(); got != tt.want {
+				t.Errorf("ProfileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinLevelByBitRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       uint8
+		bitRate     uint64
+		profileName string
+		want        uint8
+	}{
+		{"fits starting level", 40, 20000, "Main", 40},
+		{"raised by bitrate", 40, 20001, "Main", 41},
+		{"High profile multiplier", 40, 25000, "High", 40},
+		{"above High profile limit", 40, 25001, "High", 41},
+		{"High10 multiplier", 40, 60000, "High10", 40},
+		{"lossless 1080p25 High444", 40, 311040, "High444", 50},
+		{"never lowered", 51, 1000, "Main", 51},
+		{"exceeds all levels", 40, 1000001, "High", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinLevelByBitRate(tt.level, tt.bitRate, tt.profileName); got != tt.want {
+				t.Errorf("MinLevelByBitRate(%d, %d, %q) = %d, want %d", tt.level, tt.bitRate, tt.profileName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxCPBSize(t *testing.T) {
+	tests := []struct {
+		level       uint8
+		profileName string
+		want        uint32
+	}{
+		{40, "Main", 25000},
+		{40, "High", 31250},
+		{41, "High", 78125},
+		{41, "High10", 187500},
+		{51, "High444", 960000},
+	}
+	for _, tt := range tests {
+		if got := ProfileByLevel(tt.level).MaxCPBSize(tt.profileName); got != tt.want {
+			t.Errorf("MaxCPBSize(%q) of level %d = %d, want %d", tt.profileName, tt.level, got, tt.want)
+		}
+	}
+}
@@ -9,24 +9,24 @@ package avc
 // init avc package internal variables
 func init() {
 	profiles = []*AVCProfile{
-		{Level: 10, MacroBlockMax: 1485, BitRateKBMax: 64, RefFrameMax: 2},
-		{Level: 11, MacroBlockMax: 3000, BitRateKBMax: 192, RefFrameMax: 2},
-		{Level: 12, MacroBlockMax: 6000, BitRateKBMax: 384, RefFrameMax: 2},
-		{Level: 13, MacroBlockMax: 11880, BitRateKBMax: 768, RefFrameMax: 2},
-		{Level: 20, MacroBlockMax: 11880, BitRateKBMax: 2000, RefFrameMax: 2},
-		{Level: 21, MacroBlockMax: 19800, BitRateKBMax: 4000, RefFrameMax: 2},
-		{Level: 22, MacroBlockMax: 20250, BitRateKBMax: 4000, RefFrameMax: 2},
-		{Level: 30, MacroBlockMax: 40500, BitRateKBMax: 10000, RefFrameMax: 2},
-		{Level: 31, MacroBlockMax: 108000, BitRateKBMax: 14000, RefFrameMax: 3},
-		{Level: 32, MacroBlockMax: 216000, BitRateKBMax: 20000, RefFrameMax: 4},
-		{Level: 40, MacroBlockMax: 245760, BitRateKBMax: 20000, RefFrameMax: 6},
-		{Level: 41, MacroBlockMax: 245760, BitRateKBMax: 50000, RefFrameMax: 6},
-		{Level: 42, MacroBlockMax: 522240, BitRateKBMax: 50000, RefFrameMax: 7},
-		{Level: 50, MacroBlockMax: 589824, BitRateKBMax: 135000, RefFrameMax: 16},
-		{Level: 51, MacroBlockMax: 983040, BitRateKBMax: 240000, RefFrameMax: 16},
-		{Level: 52, MacroBlockMax: 2073600, BitRateKBMax: 240000, RefFrameMax: 16},
-		{Level: 60, MacroBlockMax: 4177920, BitRateKBMax: 240000, RefFrameMax: 16},
-		{Level: 61, MacroBlockMax: 8355840, BitRateKBMax: 480000, RefFrameMax: 16},
-		{Level: 62, MacroBlockMax: 16711680, BitRateKBMax: 800000, RefFrameMax: 16},
+		{Level: 10, FrameSizeMax: 99, MacroBlockMax: 1485, BitRateKBMax: 64, CPBSizeKBMax: 175, RefFrameMax: 2, FrameMbsOnly: true},
+		{Level: 11, FrameSizeMax: 396, MacroBlockMax: 3000, BitRateKBMax: 192, CPBSizeKBMax: 500, RefFrameMax: 2, FrameMbsOnly: true},
+		{Level: 12, FrameSizeMax: 396, MacroBlockMax: 6000, BitRateKBMax: 384, CPBSizeKBMax: 1000, RefFrameMax: 2, FrameMbsOnly: true},
+		{Level: 13, FrameSizeMax: 396, MacroBlockMax: 11880, BitRateKBMax: 768, CPBSizeKBMax: 2000, RefFrameMax: 2, FrameMbsOnly: true},
+		{Level: 20, FrameSizeMax: 396, MacroBlockMax: 11880, BitRateKBMax: 2000, CPBSizeKBMax: 2000, RefFrameMax: 2, FrameMbsOnly: true},
+		{Level: 21, FrameSizeMax: 792, MacroBlockMax: 19800, BitRateKBMax: 4000, CPBSizeKBMax: 4000, RefFrameMax: 2, FrameMbsOnly: false},
+		{Level: 22, FrameSizeMax: 1620, MacroBlockMax: 20250, BitRateKBMax: 4000, CPBSizeKBMax: 4000, RefFrameMax: 2, FrameMbsOnly: false},
+		{Level: 30, FrameSizeMax: 1620, MacroBlockMax: 40500, BitRateKBMax: 10000, CPBSizeKBMax: 10000, RefFrameMax: 2, FrameMbsOnly: false},
+		{Level: 31, FrameSizeMax: 3600, MacroBlockMax: 108000, BitRateKBMax: 14000, CPBSizeKBMax: 14000, RefFrameMax: 3, FrameMbsOnly: false},
+		{Level: 32, FrameSizeMax: 5120, MacroBlockMax: 216000, BitRateKBMax: 20000, CPBSizeKBMax: 20000, RefFrameMax: 4, FrameMbsOnly: false},
+		{Level: 40, FrameSizeMax: 8192, MacroBlockMax: 245760, BitRateKBMax: 20000, CPBSizeKBMax: 25000, RefFrameMax: 6, FrameMbsOnly: false},
+		{Level: 41, FrameSizeMax: 8192, MacroBlockMax: 245760, BitRateKBMax: 50000, CPBSizeKBMax: 62500, RefFrameMax: 6, FrameMbsOnly: false},
+		{Level: 42, FrameSizeMax: 8704, MacroBlockMax: 522240, BitRateKBMax: 50000, CPBSizeKBMax: 62500, RefFrameMax: 7, FrameMbsOnly: true},
+		{Level: 50, FrameSizeMax: 22080, MacroBlockMax: 589824, BitRateKBMax: 135000, CPBSizeKBMax: 135000, RefFrameMax: 16, FrameMbsOnly: true},
+		{Level: 51, FrameSizeMax: 36864, MacroBlockMax: 983040, BitRateKBMax: 240000, CPBSizeKBMax: 240000, RefFrameMax: 16, FrameMbsOnly: true},
+		{Level: 52, FrameSizeMax: 36864, MacroBlockMax: 2073600, BitRateKBMax: 240000, CPBSizeKBMax: 240000, RefFrameMax: 16, FrameMbsOnly: true},
+		{Level: 60, FrameSizeMax: 139264, MacroBlockMax: 4177920, BitRateKBMax: 240000, CPBSizeKBMax: 240000, RefFrameMax: 16, FrameMbsOnly: true},
+		{Level: 61, FrameSizeMax: 139264, MacroBlockMax: 8355840, BitRateKBMax: 480000, CPBSizeKBMax: 480000, RefFrameMax: 16, FrameMbsOnly: true},
+		{Level: 62, FrameSizeMax: 139264, MacroBlockMax: 16711680, BitRateKBMax: 800000, CPBSizeKBMax: 800000, RefFrameMax: 16, FrameMbsOnly: true},
 	}
 }
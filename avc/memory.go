@@ -0,0 +1,37 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import (
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// DPBFrameMax is the maximum number of pictures in the decoded picture buffer of any level.
+const DPBFrameMax = 16
+
+// Memory is the memory model of x264. Reference pictures keep 3 half-pel interpolated luma planes
+// besides the picture, and lookahead pictures keep a half resolution copy with its interpolated planes.
+var Memory = encode.MemoryModel{
+	ReferenceFactor: 3.2,
+	LookaheadFactor: 1.7,
+	ThreadMiB:       4,
+	BaseMiB:         32,
+}
+
+// Return the estimated memory in MiB of x264 encoding and of decoding the stream.
+// Every thread of x264 encodes a frame, and lookahead holds the rate control lookahead
+// and the frames buffered by sync lookahead. Interlaced video is stored as frames.
+// The decoder keeps the reference frames, limited by DPBFrameMax, and the frame being decoded.
+func EstimateMemory(width, height uint32, bitDepth uint8, chromaFormat video.ChromaFormat, refFrames, bFrames uint8, rcLookahead uint16, syncLookahead, threads uint8) (encoderMiB, decoderMiB uint32) {
+	codedWidth, codedHeight := CodedSize(width, height, video.Progressive)
+	pictureBytes := encode.PictureBytes(codedWidth, codedHeight, bitDepth, chromaFormat)
+	lookahead := rcLookahead + uint16(syncLookahead)
+	encoderMiB = Memory.Encoder(pictureBytes, refFrames, bFrames, lookahead, uint16(threads), uint16(threads))
+	decoderMiB = encode.DecoderMemory(pictureBytes, min(refFrames, DPBFrameMax)+1)
+	return encoderMiB, decoderMiB
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestEstimateMemory(t *testing.T) {
+	tests := []struct {
+		name        string
+		refFrames   uint8
+		threads     uint8
+		wantEncoder uint32
+		wantDecoder uint32
+	}{
+		{"1080p", 4, 16, 709, 15},
+		{"DPB limited to 16 frames", 17, 16, 834, 51},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, decoder := EstimateMemory(1920, 1080, 8, video.Chroma420, tt.refFrames, 3, 50, 30, tt.threads)
+			if encoder != tt.wantEncoder || decoder != tt.wantDecoder {
+				t.Errorf("EstimateMemory() = %d, %d, want %d, %d", encoder, decoder, tt.wantEncoder, tt.wantDecoder)
+			}
+		})
+	}
+}
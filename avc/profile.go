@@ -6,45 +6,120 @@
 
 package avc
 
-var profiles []*AVCProfile
-
-// RateFactor represents the Constant Rate Factor (CRF) for encoding profiles.
-type RateFactor float64
-
-const (
-	NormalQuality RateFactor = 24
-	HighQuality   RateFactor = 20
-	UltraQuality  RateFactor = 16
+import (
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
 )
 
+var profiles []*AVCProfile
+
 // EncodeProfile contains minimum parameters for encoding video in AVC.
+// Width and Height are the stored size, SampleAspect is treated as square pixels when zero.
+// FrameRate is the nominal frame rate, PeakFrameRate is the highest frame rate of
+// variable frame rate sources and is zero for constant frame rate.
+// ScanType and FieldOrder describe the source scan, see video.Resolution.
+// Color is the color description, which is video.DefaultColor of the size when zero.
+// ColorFromInput lets the encoder adjust the color description to the input, Color is then the fallback.
+// BitDepth and ChromaFormat are the output format, BitDepth is 8 when zero.
+// Lossless selects lossless coding, which ignores RateFactor, and GOPMode is the GOP structure.
+// RateControl selects the rate control mode, RateFactor is ignored by bitrate modes.
+// Proxy restricts the profile to Main profile, which is decoded by all players and NLEs.
+// Source identifies the profile matrix entry the profile is generated from.
 type EncodeProfile struct {
-	Name        string
-	Width       uint16
-	Height      uint16
-	FrameRate   float64
-	RateFactor  RateFactor
-	ThreadCount uint8
+	Name           string
+	Width          uint32
+	Height         uint32
+	SampleAspect   video.Ratio
+	FrameRate      video.FrameRate
+	PeakFrameRate  video.FrameRate
+	ScanType       video.ScanType
+	FieldOrder     video.FieldOrder
+	Color          video.ColorDescription
+	ColorFromInput bool
+	BitDepth       uint8
+	ChromaFormat   video.ChromaFormat
+	RateFactor     RateFactor
+	Lossless       bool
+	RateControl    encode.RateControl
+	GOPMode        encode.GOPMode
+	Proxy          bool
+	ThreadCount    uint8
+	Source         string
+}
+
+// Return true if the profile targets variable frame rate sources.
+func (p *EncodeProfile) IsVariableFrameRate() bool {
+	return !p.PeakFrameRate.IsZero() && p.PeakFrameRate != p.FrameRate
+}
+
+// Return the output bit depth, which is 8 if not defined.
+func (p *EncodeProfile) OutputBitDepth() uint8 {
+	if p.BitDepth == 0 {
+		return 8
+	}
+	return p.BitDepth
+}
+
+// Return the color description, which is the default of the size if not defined.
+func (p *EncodeProfile) ColorDescription() video.ColorDescription {
+	if p.Color.IsZero() {
+		return video.DefaultColor(p.Width, p.Height)
+	}
+	return p.Color
+}
+
+// Return the frame rate used to determine level,
+// which is the peak frame rate for variable frame rate sources.
+func (p *EncodeProfile) LevelFrameRate() video.FrameRate {
+	if p.IsVariableFrameRate() {
+		return p.PeakFrameRate
+	}
+	return p.FrameRate
 }
 
 // AVCProfile contains all constraints of an AVC Level.
+// FrameSizeMax and MacroBlockMax are the maximum frame size and processing rate in macroblocks.
+// BitRateKBMax and CPBSizeKBMax are the maximum bitrate and coded picture buffer size in kbits of Main profile.
+// FrameMbsOnly levels do not allow field coding, which is required by interlaced video.
 type AVCProfile struct {
 	Level         uint8
-	MacroBlockMax uint32
+	FrameSizeMax  uint64
+	MacroBlockMax uint64
 	BitRateKBMax  uint32
+	CPBSizeKBMax  uint32
 	RefFrameMax   uint8
+	FrameMbsOnly  bool
 }
 
-// Return minimum AVC level for specified resolution and framerate.
-func MinLevel(width, height uint16, framerate float64) uint8 {
-	if width == 0 || height == 0 || framerate <= 0 {
+// Return minimum AVC level for specified resolution, framerate and scan type.
+// Frame size and macroblock rate are counted on the coded size, and dimensions must not exceed
+// square root of 8 times the maximum frame size.
+// Field coded video is only allowed from level 2.1 to level 4.1, return 0 if no level fits.
+func MinLevel(width, height uint32, framerate video.FrameRate, scanType video.ScanType) uint8 {
+	if width == 0 || height == 0 || framerate.IsZero() {
 		return 0
 	}
-	requiredMacroBlocks := uint32(float64(width) * float64(height) * framerate / float64(256))
+	codedWidth, codedHeight := CodedSize(width, height, scanType)
+	picturesPerFrame := uint64(1)
+	if scanType.IsFieldCoded() {
+		picturesPerFrame = 2
+	}
+	widthInMbs := uint64(codedWidth / MacroBlockSize)
+	frameHeightInMbs := uint64(codedHeight/MacroBlockSize) * picturesPerFrame
+	frameSize := widthInMbs * frameHeightInMbs
+	// compare MaxMBPS >= frameSize * num / den without rounding
+	requiredMacroBlocks := frameSize * uint64(framerate.Num)
 
 	level := uint8(0)
 	for _, profile := range profiles {
-		if profile.MacroBlockMax >= requiredMacroBlocks {
+		if scanType.IsFieldCoded() && profile.FrameMbsOnly {
+			continue
+		}
+		frameSizeMax := profile.FrameSizeMax
+		if frameSize > frameSizeMax || widthInMbs*widthInMbs > frameSizeMax*8 || frameHeightInMbs*frameHeightInMbs > frameSizeMax*8 {
+			continue
+		}
+		if profile.MacroBlockMax*uint64(framerate.Den) >= requiredMacroBlocks {
 			level = profile.Level
 			break
 		}
@@ -62,9 +137,12 @@ func ProfileByLevel(level uint8) *AVCProfile {
 		if profile.Level == level {
 			return &AVCProfile{
 				Level:         profile.Level,
+				FrameSizeMax:  profile.FrameSizeMax,
 				MacroBlockMax: profile.MacroBlockMax,
 				BitRateKBMax:  profile.BitRateKBMax,
+				CPBSizeKBMax:  profile.CPBSizeKBMax,
 				RefFrameMax:   profile.RefFrameMax,
+				FrameMbsOnly:  profile.FrameMbsOnly,
 			}
 		}
 	}
@@ -0,0 +1,91 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestLevelFrameRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		frameRate     video.FrameRate
+		peakFrameRate video.FrameRate
+		wantVariable  bool
+		wantLevel     video.FrameRate
+	}{
+		{"constant", video.FPS30, video.FrameRate{}, false, video.FPS30},
+		{"peak equals nominal", video.FPS30, video.FPS30, false, video.FPS30},
+		{"nominal 30 peak 60", video.FPS30, video.FPS60, true, video.FPS60},
+		{"ntsc nominal and peak", video.FPS29970, video.FPS59940, true, video.FPS59940},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &EncodeProfile{Width: 1920, Height: 1080, FrameRate: tt.frameRate, PeakFrameRate: tt.peakFrameRate}
+			if got := profile.IsVariableFrameRate(); got != tt.wantVariable {
+				t.Errorf("IsVariableFrameRate() = %v, want %v", got, tt.wantVariable)
+			}
+			if got := profile.LevelFrameRate(); got != tt.wantLevel {
+				t.Errorf("LevelFrameRate() = %v, want %v", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestMinLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     uint32
+		height    uint32
+		frameRate video.FrameRate
+		scanType  video.ScanType
+		want      uint8
+	}{
+		{"1080p25", 1920, 1080, video.FPS25, video.Progressive, 40},
+		{"1080p23.976", 1920, 1080, video.FPS23976, video.Progressive, 40},
+		{"1080p30 at MaxMBPS of 4", 1920, 1080, video.FPS30, video.Progressive, 40},
+		{"1080p50", 1920, 1080, video.FPS50, video.Progressive, 42},
+		{"1080p60", 1920, 1080, video.FPS60, video.Progressive, 42},
+		{"1080i25", 1920, 1080, video.FPS25, video.Interlaced, 40},
+		{"1080i29.97", 1920, 1080, video.FPS29970, video.Interlaced, 40},
+		{"1080psf25", 1920, 1080, video.FPS25, video.FakeInterlaced, 40},
+		{"1080i50 beyond field levels", 1920, 1080, video.FPS50, video.Interlaced, 0},
+		{"576i25 at MaxMBPS of 3", 720, 576, video.FPS25, video.Interlaced, 30},
+		{"2160p30", 3840, 2160, video.FPS30, video.Progressive, 51},
+		{"2160p60", 3840, 2160, video.FPS60, video.Progressive, 52},
+		{"4320p60", 7680, 4320, video.FPS60, video.Progressive, 61},
+		{"4320p120", 7680, 4320, video.FPS120, video.Progressive, 62},
+		{"beyond all levels", 15360, 8640, video.FPS30, video.Progressive, 0},
+		{"beyond 16-bit size", 65536, 1080, video.FPS25, video.Progressive, 0},
+		{"4320p30", 7680, 4320, video.FPS30, video.Progressive, 60},
+		{"wide strip limited by dimension", 4096, 16, video.FPS25, video.Progressive, 40},
+		{"zero width", 0, 1080, video.FPS25, video.Progressive, 0},
+		{"zero frame rate", 1920, 1080, video.FrameRate{}, video.Progressive, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinLevel(tt.width, tt.height, tt.frameRate, tt.scanType); got != tt.want {
+				t.Errorf("MinLevel(%d, %d, %v, %v) = %d, want %d", tt.width, tt.height, tt.frameRate, tt.scanType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfileByLevel(t *testing.T) {
+	if got := ProfileByLevel(0); got != nil {
+		t.Errorf("ProfileByLevel(0) = %v, want nil", got)
+	}
+	if got := ProfileByLevel(33); got != nil {
+		t.Errorf("ProfileByLevel(33) = %v, want nil", got)
+	}
+	want := AVCProfile{Level: 41, FrameSizeMax: 8192, MacroBlockMax: 245760, BitRateKBMax: 50000, CPBSizeKBMax: 62500, RefFrameMax: 6}
+	if got := ProfileByLevel(41); got == nil || *got != want {
+		t.Errorf("ProfileByLevel(41) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import "github.com/lukaz17/hybrid-profile-generator-go/encode"
+
+// RateFactor represents the Constant Rate Factor (CRF) for encoding profiles.
+type RateFactor float64
+
+const (
+	NormalQuality  RateFactor = 24
+	HighQuality    RateFactor = 20
+	UltraQuality   RateFactor = 16
+	PreviewQuality RateFactor = 26
+)
+
+// Tiers is the quality ladder of AVC, which names profiles and selects encoder heuristics.
+var Tiers = encode.QualityLadder{
+	{Name: "X", RateFactor: float64(UltraQuality), RateFactorMax: 17},
+	{Name: "H", RateFactor: float64(HighQuality), RateFactorMax: 22},
+	{Name: "L", RateFactor: float64(NormalQuality), RateFactorMax: 51},
+}
+
+// Equivalence maps rate factors of AVC to the reference scale, which is x264 itself.
+var Equivalence = encode.Equivalence{}
+
+// Return the quality tier of the rate factor.
+func (r RateFactor) Tier() encode.QualityTier {
+	return Tiers.Tier(float64(r))
+}
+
+// Return the position of the quality tier of the rate factor, 0 is the best quality.
+func (r RateFactor) TierIndex() int {
+	return Tiers.Index(float64(r))
+}
+
+// Return the label of the rate factor used in profile name, see encode.QualityLadder.Label.
+func (r RateFactor) Label() string {
+	return Tiers.Label(float64(r))
+}
+
+// Return the rate factor of AVC equivalent to the rate factor of x264.
+func EquivalentRateFactor(reference float64) RateFactor {
+	return RateFactor(Equivalence.FromReference(reference))
+}
+
+// Return the rate factor of x264 equivalent to the rate factor.
+func (r RateFactor) Reference() float64 {
+	return Equivalence.ToReference(float64(r))
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import "testing"
+
+func TestRateFactorTier(t *testing.T) {
+	tests := []struct {
+		rateFactor RateFactor
+		wantIndex  int
+		wantLabel  string
+	}{
+		{UltraQuality, 0, "X"},
+		{17, 0, "X17"},
+		{HighQuality, 1, "H"},
+		{22, 1, "H22"},
+		{NormalQuality, 2, "L"},
+		{PreviewQuality, 2, "L26"},
+	}
+	for _, tt := range tests {
+		if got := tt.rateFactor.TierIndex(); got != tt.wantIndex {
+			t.Errorf("RateFactor(%v).TierIndex() = %d, want %d", tt.rateFactor, got, tt.wantIndex)
+		}
+		if got := tt.rateFactor.Label(); got != tt.wantLabel {
+			t.Errorf("RateFactor(%v).Label() = %q, want %q", tt.rateFactor, got, tt.wantLabel)
+		}
+	}
+}
+
+func TestEquivalentRateFactor(t *testing.T) {
+	// x264 is the reference scale
+	for _, reference := range []float64{16, 20, 22.5, 26} {
+		got := EquivalentRateFactor(reference)
+		if got != RateFactor(reference) {
+			t.Errorf("EquivalentRateFactor(%v) = %v, want %v", reference, got, reference)
+		}
+		if got.Reference() != reference {
+			t.Errorf("RateFactor(%v).Reference() = %v, want %v", got, got.Reference(), reference)
+		}
+	}
+}
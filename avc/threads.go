@@ -0,0 +1,28 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// ThreadMax is the maximum number of frame threads of x264.
+const ThreadMax = 128
+
+// Return the maximum number of frame threads for the picture height.
+// A frame thread waits for the rows of the reference frame within the motion search range,
+// so more threads than half of the macroblock rows of each coded picture, see CodedSize, only add latency.
+func MaxThreads(height uint32, scanType video.ScanType) uint8 {
+	_, codedHeight := CodedSize(0, height, scanType)
+	threads := (codedHeight/MacroBlockSize + 1) / 2
+	return uint8(min(max(threads, 1), ThreadMax))
+}
+
+// Return the number of frame threads for the number of logical cores and picture height,
+// which follows the default of x264 (1.5 threads per core) limited by MaxThreads.
+func Threads(logicalCores uint16, height uint32, scanType video.ScanType) uint8 {
+	threads := max(uint32(logicalCores)*3/2, 1)
+	return min(uint8(min(threads, ThreadMax)), MaxThreads(height, scanType))
+}
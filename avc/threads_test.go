@@ -0,0 +1,62 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package avc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestMaxThreads(t *testing.T) {
+	tests := []struct {
+		name     string
+		height   uint32
+		scanType video.ScanType
+		want     uint8
+	}{
+		{"1080p", 1080, video.Progressive, 34},
+		{"1080i", 1080, video.Interlaced, 17},
+		{"576i", 576, video.Interlaced, 9},
+		{"1080psf", 1080, video.FakeInterlaced, 17},
+		{"1080 telecined", 1080, video.Telecined, 34},
+		{"720p", 720, video.Progressive, 23},
+		{"single row", 16, video.Progressive, 1},
+		{"4320p", 4320, video.Progressive, 128},
+		{"8640p", 8640, video.Progressive, ThreadMax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxThreads(tt.height, tt.scanType); got != tt.want {
+				t.Errorf("MaxThreads(%d, %v) = %d, want %d", tt.height, tt.scanType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThreads(t *testing.T) {
+	tests := []struct {
+		name         string
+		logicalCores uint16
+		height       uint32
+		scanType     video.ScanType
+		want         uint8
+	}{
+		{"8 cores 1080p", 8, 1080, video.Progressive, 12},
+		{"64 cores 1080p", 64, 1080, video.Progressive, 34},
+		{"64 cores 1080i", 64, 1080, video.Interlaced, 17},
+		{"64 cores 1080psf", 64, 1080, video.FakeInterlaced, 17},
+		{"no cores", 0, 1080, video.Progressive, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Threads(tt.logicalCores, tt.height, tt.scanType); got != tt.want {
+				t.Errorf("Threads(%d, %d, %v) = %d, want %d", tt.logicalCores, tt.height, tt.scanType, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package cmdline
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Option is a single command-line option. Value is empty for options without value.
+type Option struct {
+	Name  string
+	Value string
+}
+
+// Arguments holds the executable and its command-line options in order.
+type Arguments struct {
+	Binary  string
+	Options []Option
+}
+
+// Return new Arguments for specified executable.
+func New(binary string) *Arguments {
+	return &Arguments{
+		Binary:  binary,
+		Options: []Option{},
+	}
+}
+
+// Append an option with its value, e.g. --crf 20.
+func (a *Arguments) Add(name, value string) *Arguments {
+	a.Options = append(a.Options, Option{Name: name, Value: value})
+	return a
+}
+
+// Append an option without value, e.g. --high-tier.
+func (a *Arguments) Flag(name string) *Arguments {
+	a.Options = append(a.Options, Option{Name: name})
+	return a
+}
+
+// Return the options as list of arguments, without the executable.
+func (a *Arguments) Args() []string {
+	args := []string{}
+	for _, option := range a.Options {
+		args = append(args, "--"+option.Name)
+		if option.Value != "" {
+			args = append(args, option.Value)
+		}
+	}
+	return args
+}
+
+// Return the options except the ones with specified names.
+func (a *Arguments) Without(names ...string) []Option {
+	options := []Option{}
+	for _, option := range a.Options {
+		if !slices.Contains(names, option.Name) {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// Return the arguments as a JSON array, without the executable.
+func (a *Arguments) JSON() ([]byte, error) {
+	return json.MarshalIndent(a.Args(), "", "  ")
+}
+
+// Return a POSIX shell snippet running the executable with the arguments.
+// Additional arguments passed to the snippet, like input and output files, are appended.
+func (a *Arguments) Shell(comment string) string {
+	builder := strings.Builder{}
+	builder.WriteString("#!/bin/sh\n")
+	if comment != "" {
+		builder.WriteString("# " + comment + "\n")
+	}
+	builder.WriteString("exec " + Quote(a.Binary))
+	for _, arg := range a.Args() {
+		builder.WriteString(" " + Quote(arg))
+	}
+	builder.WriteString(" \"$@\"\n")
+	return builder.String()
+}
+
+// Join the options in key=value:key=value form accepted by libx264 and libx265 param parsers.
+// Option without value is treated as a boolean switch.
+func JoinParams(options []Option) string {
+	params := make([]string, 0, len(options))
+	for _, option := range options {
+		value := option.Value
+		if value == "" {
+			value = "1"
+		}
+		// colon is the separator of params, encoders also accept comma for paired values
+		params = append(params, option.Name+"="+strings.ReplaceAll(value, ":", ","))
+	}
+	return strings.Join(params, ":")
+}
+
+// Quote the argument for POSIX shell if it contains any special character.
+func Quote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range arg {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.,:/=+@%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package cmdline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{"plain", "x264", "x264"},
+		{"option", "--crf", "--crf"},
+		{"decimal", "20.5", "20.5"},
+		{"paired values", "-2:-1", "-2:-1"},
+		{"ratio and path", "32:27,/tmp/a=b+c@d%", "32:27,/tmp/a=b+c@d%"},
+		{"empty", "", "''"},
+		{"space", "x264 1920x1080@25.00-H", "'x264 1920x1080@25.00-H'"},
+		{"single quote", "it's", `'it'\''s'`},
+		{"only single quote", "'", `''\'''`},
+		{"dollar", "$HOME", "'$HOME'"},
+		{"double quote", `a"b`, `'a"b'`},
+		{"parentheses", "G(13250,34500)", "'G(13250,34500)'"},
+		{"glob", "*.mkv", "'*.mkv'"},
+		{"semicolon", "a;rm", "'a;rm'"},
+		{"newline", "a\nb", "'a\nb'"},
+		{"non ASCII", "café", "'café'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Quote(tt.arg); got != tt.want {
+				t.Errorf("Quote(%q) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArguments(t *testing.T) {
+	args := New("x264").
+		Add("crf", "20").
+		Flag("no-scenecut").
+		Add("sar", "32:27").
+		Add("dolby-vision-rpu", "my rpu.bin")
+
+	wantOptions := []Option{{"crf", "20"}, {"no-scenecut", ""}, {"sar", "32:27"}, {"dolby-vision-rpu", "my rpu.bin"}}
+	if !reflect.DeepEqual(args.Options, wantOptions) {
+		t.Errorf("Options = %q, want %q", args.Options, wantOptions)
+	}
+	wantArgs := []string{"--crf", "20", "--no-scenecut", "--sar", "32:27", "--dolby-vision-rpu", "my rpu.bin"}
+	if got := args.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("Args() = %q, want %q", got, wantArgs)
+	}
+
+	json, err := args.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := "[\n  \"--crf\",\n  \"20\",\n  \"--no-scenecut\",\n  \"--sar\",\n  \"32:27\",\n  \"--dolby-vision-rpu\",\n  \"my rpu.bin\"\n]"
+	if string(json) != wantJSON {
+		t.Errorf("JSON() = %s, want %s", json, wantJSON)
+	}
+
+	tests := []struct {
+		comment string
+		want    string
+	}{
+		{"x264 test", "#!/bin/sh\n# x264 test\nexec x264 --crf 20 --no-scenecut --sar 32:27 --dolby-vision-rpu 'my rpu.bin' \"$@\"\n"},
+		{"", "#!/bin/sh\nexec x264 --crf 20 --no-scenecut --sar 32:27 --dolby-vision-rpu 'my rpu.bin' \"$@\"\n"},
+	}
+	for _, tt := range tests {
+		if got := args.Shell(tt.comment); got != tt.want {
+			t.Errorf("Shell(%q) = %q, want %q", tt.comment, got, tt.want)
+		}
+	}
+}
+
+func TestWithout(t *testing.T) {
+	args := New("x264").Add("profile", "high").Add("level", "4.1").Add("crf", "20").Add("ref", "5")
+	tests := []struct {
+		name  string
+		names []string
+		want  []Option
+	}{
+		{"none", nil, []Option{{"profile", "high"}, {"level", "4.1"}, {"crf", "20"}, {"ref", "5"}}},
+		{"some", []string{"profile", "crf"}, []Option{{"level", "4.1"}, {"ref", "5"}}},
+		{"unknown", []string{"preset"}, []Option{{"profile", "high"}, {"level", "4.1"}, {"crf", "20"}, {"ref", "5"}}},
+		{"all", []string{"profile", "level", "crf", "ref"}, []Option{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := args.Without(tt.names...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Without(%q) = %q, want %q", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{"none", nil, ""},
+		{"single", []Option{{"ref", "5"}}, "ref=5"},
+		{"switch", []Option{{"no-sao", ""}}, "no-sao=1"},
+		{"several", []Option{{"ref", "5"}, {"bframes", "8"}, {"no-scenecut", ""}}, "ref=5:bframes=8:no-scenecut=1"},
+		{"colon in value", []Option{{"deblock", "-4:-3"}, {"sar", "32:27"}}, "deblock=-4,-3:sar=32,27"},
+		{"equal sign in value", []Option{{"dolby-vision-rpu", "title=a.rpu"}}, "dolby-vision-rpu=title=a.rpu"},
+		{"parentheses and comma", []Option{{"master-display", "G(13250,34500)L(10000000,1)"}}, "master-display=G(13250,34500)L(10000000,1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JoinParams(tt.options); got != tt.want {
+				t.Errorf("JoinParams() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package cmdline defines native command-line arguments of encoders
+and renders them as shell snippets or JSON argument arrays.
+*/
+package cmdline
@@ -0,0 +1,82 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// GOPMode defines the structure of group of pictures.
+type GOPMode uint8
+
+const (
+	// LongGOP has a keyframe every 10 seconds at most, for delivery.
+	LongGOP GOPMode = iota
+	// ShortGOP has a keyframe every second at most, for mezzanine files that are cut frequently.
+	ShortGOP
+	// IntraOnly codes every frame as a keyframe, for archiving.
+	IntraOnly
+	// EditGOP has a closed GOP of 1 second with at most 2 B-frames and fast decoding, for editing.
+	EditGOP
+	// EditIntra codes every frame as a keyframe with fast decoding, for editing.
+	EditIntra
+)
+
+// Return the maximum keyframe interval in frames at specified frame rate.
+func (m GOPMode) KeyInterval(frameRate video.FrameRate) uint32 {
+	switch m {
+	case ShortGOP, EditGOP:
+		return frameRate.Frames(1)
+	case IntraOnly, EditIntra:
+		return 1
+	}
+	return frameRate.Frames(10)
+}
+
+// Return the minimum keyframe interval in frames at specified frame rate.
+// Edit modes have fixed keyframe interval, so keyframes are never inserted at scene cuts.
+// Return 0 for automatic, which allows keyframes at scene cuts.
+func (m GOPMode) MinKeyInterval(frameRate video.FrameRate) uint32 {
+	if m.IsEdit() {
+		return m.KeyInterval(frameRate)
+	}
+	return 0
+}
+
+// Return the maximum number of consecutive B-frames allowed by the GOP structure.
+func (m GOPMode) MaxBFrames() uint8 {
+	switch m {
+	case EditGOP:
+		return 2
+	case IntraOnly, EditIntra:
+		return 0
+	}
+	return 16
+}
+
+// Return true if the GOP contains only keyframes, so B-frames and lookahead are useless.
+func (m GOPMode) IsIntraOnly() bool {
+	return m == IntraOnly || m == EditIntra
+}
+
+// Return true for edit modes, which disable scene cut detection and use options friendly to fast decoding.
+func (m GOPMode) IsEdit() bool {
+	return m == EditGOP || m == EditIntra
+}
+
+// Return the short label used in profile name, e.g. "intra" for IntraOnly, or empty for LongGOP.
+func (m GOPMode) Label() string {
+	switch m {
+	case ShortGOP:
+		return "sgop"
+	case IntraOnly:
+		return "intra"
+	case EditGOP:
+		return "edit"
+	case EditIntra:
+		return "editintra"
+	}
+	return ""
+}
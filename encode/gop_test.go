@@ -0,0 +1,55 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestGOPMode(t *testing.T) {
+	tests := []struct {
+		mode               GOPMode
+		frameRate          video.FrameRate
+		wantKeyInterval    uint32
+		wantMinKeyInterval uint32
+		wantMaxBFrames     uint8
+		wantIntraOnly      bool
+		wantEdit           bool
+		wantLabel          string
+	}{
+		{LongGOP, video.FPS25, 250, 0, 16, false, false, ""},
+		{LongGOP, video.FPS23976, 240, 0, 16, false, false, ""},
+		{ShortGOP, video.FPS25, 25, 0, 16, false, false, "sgop"},
+		{ShortGOP, video.FPS29970, 30, 0, 16, false, false, "sgop"},
+		{IntraOnly, video.FPS25, 1, 0, 0, true, false, "intra"},
+		{EditGOP, video.FPS25, 25, 25, 2, false, true, "edit"},
+		{EditGOP, video.FPS59940, 60, 60, 2, false, true, "edit"},
+		{EditIntra, video.FPS25, 1, 1, 0, true, true, "editintra"},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.KeyInterval(tt.frameRate); got != tt.wantKeyInterval {
+			t.Errorf("%q KeyInterval(%v) = %d, want %d", tt.mode.Label(), tt.frameRate, got, tt.wantKeyInterval)
+		}
+		if got := tt.mode.MinKeyInterval(tt.frameRate); got != tt.wantMinKeyInterval {
+			t.Errorf("%q MinKeyInterval(%v) = %d, want %d", tt.mode.Label(), tt.frameRate, got, tt.wantMinKeyInterval)
+		}
+		if got := tt.mode.MaxBFrames(); got != tt.wantMaxBFrames {
+			t.Errorf("%q MaxBFrames() = %d, want %d", tt.mode.Label(), got, tt.wantMaxBFrames)
+		}
+		if got := tt.mode.IsIntraOnly(); got != tt.wantIntraOnly {
+			t.Errorf("%q IsIntraOnly() = %v, want %v", tt.mode.Label(), got, tt.wantIntraOnly)
+		}
+		if got := tt.mode.IsEdit(); got != tt.wantEdit {
+			t.Errorf("%q IsEdit() = %v, want %v", tt.mode.Label(), got, tt.wantEdit)
+		}
+		if got := tt.mode.Label(); got != tt.wantLabel {
+			t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// LosslessRatio is the typical compression ratio of lossless coding of camera footage.
+const LosslessRatio = 2
+
+// Return the estimated bitrate in kbps of lossless coding of video of specified size, frame rate and format,
+// which is used to choose a level that can carry the stream.
+func LosslessBitRate(width, height uint32, frameRate video.FrameRate, bitDepth uint8, chromaFormat video.ChromaFormat) uint64 {
+	return video.RawBitRate(width, height, frameRate, bitDepth, chromaFormat) / LosslessRatio
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestLosslessBitRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		width        uint32
+		height       uint32
+		frameRate    video.FrameRate
+		bitDepth     uint8
+		chromaFormat video.ChromaFormat
+		want         uint64
+	}{
+		{"1080p25 8-bit 4:2:0", 1920, 1080, video.FPS25, 8, video.Chroma420, 311040},
+		{"1080p25 10-bit 4:2:2", 1920, 1080, video.FPS25, 10, video.Chroma422, 518400},
+		{"2160p25 10-bit 4:2:2", 3840, 2160, video.FPS25, 10, video.Chroma422, 2073600},
+		{"1080p25 8-bit 4:4:4", 1920, 1080, video.FPS25, 8, video.Chroma444, 622080},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LosslessBitRate(tt.width, tt.height, tt.frameRate, tt.bitDepth, tt.chromaFormat); got != tt.want {
+				t.Errorf("LosslessBitRate() = %d, want %d", got, tt.want)
+			}
+			// lossless coding halves the raw bitrate
+			raw := video.RawBitRate(tt.width, tt.height, tt.frameRate, tt.bitDepth, tt.chromaFormat)
+			if raw/LosslessRatio != tt.want {
+				t.Errorf("RawBitRate() / LosslessRatio = %d, want %d", raw/LosslessRatio, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"math"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// MiB is the number of bytes in a mebibyte.
+const MiB = 1024 * 1024
+
+// MemoryModel describes how an encoder keeps pictures in memory, which is used to estimate its footprint.
+// ReferenceFactor is the size of a reference or reconstructed picture relative to a source picture,
+// including padding and interpolated planes. LookaheadFactor is the size of a picture queued in lookahead
+// relative to a source picture, including its downscaled copy and analysis data.
+// ThreadMiB is the scratch memory of each worker thread and BaseMiB is the fixed overhead of the encoder.
+type MemoryModel struct {
+	ReferenceFactor float64
+	LookaheadFactor float64
+	ThreadMiB       float64
+	BaseMiB         float64
+}
+
+// Return the bytes of a picture of specified size and format held by encoders and decoders,
+// where samples of more than 8 bits are stored in 16 bits.
+func PictureBytes(width, height uint32, bitDepth uint8, chromaFormat video.ChromaFormat) uint64 {
+	bytesPerSample := float64(1)
+	if bitDepth > 8 {
+		bytesPerSample = 2
+	}
+	return uint64(float64(width) * float64(height) * chromaFormat.SamplesPerPixel() * bytesPerSample)
+}
+
+// Return the estimated encoder memory in MiB for pictures of pictureBytes.
+// Each of frameThreads concurrent frames keeps its reconstructed picture besides refFrames references,
+// and lookahead holds lookahead pictures plus the B-frames waiting for their next reference.
+func (m MemoryModel) Encoder(pictureBytes uint64, refFrames, bFrames uint8, lookahead, frameThreads, threads uint16) uint32 {
+	references := float64(refFrames) + float64(frameThreads)
+	queued := float64(lookahead) + float64(bFrames)
+	pictures := references*m.ReferenceFactor + queued*m.LookaheadFactor
+	total := pictures*float64(pictureBytes)/MiB + float64(threads)*m.ThreadMiB + m.BaseMiB
+	return uint32(math.Ceil(total))
+}
+
+// Return the estimated decoder memory in MiB of the decoded picture buffer for pictures of pictureBytes,
+// which holds dpbFrames pictures including the picture being decoded.
+func DecoderMemory(pictureBytes uint64, dpbFrames uint8) uint32 {
+	return uint32(math.Ceil(float64(pictureBytes) * float64(dpbFrames) / MiB))
+}
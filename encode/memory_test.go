@@ -0,0 +1,81 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestPictureBytes(t *testing.T) {
+	tests := []struct {
+		name         string
+		bitDepth     uint8
+		chromaFormat video.ChromaFormat
+		want         uint64
+	}{
+		{"8-bit 4:2:0", 8, video.Chroma420, 3110400},
+		{"10-bit 4:2:0", 10, video.Chroma420, 6220800},
+		{"12-bit 4:2:0", 12, video.Chroma420, 6220800},
+		{"8-bit 4:2:2", 8, video.Chroma422, 4147200},
+		{"10-bit 4:4:4", 10, video.Chroma444, 12441600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PictureBytes(1920, 1080, tt.bitDepth, tt.chromaFormat); got != tt.want {
+				t.Errorf("PictureBytes(1920, 1080, %d, %v) = %d, want %d", tt.bitDepth, tt.chromaFormat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryModelEncoder(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        MemoryModel
+		refFrames    uint8
+		bFrames      uint8
+		lookahead    uint16
+		frameThreads uint16
+		threads      uint16
+		want         uint32
+	}{
+		{"pictures only", MemoryModel{1, 1, 0, 0}, 3, 2, 10, 2, 4, 17},
+		{"thread and base overhead", MemoryModel{1, 1, 2, 10}, 3, 2, 10, 2, 4, 35},
+		{"reference factor", MemoryModel{2, 1, 0, 0}, 3, 2, 10, 2, 4, 22},
+		{"lookahead factor", MemoryModel{1, 0.5, 0, 0}, 3, 2, 10, 2, 4, 11},
+		{"rounded up", MemoryModel{1, 1, 0, 0.1}, 1, 0, 0, 0, 0, 2},
+		{"intra only without lookahead", MemoryModel{1, 1, 0, 0}, 0, 0, 0, 1, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.model.Encoder(MiB, tt.refFrames, tt.bFrames, tt.lookahead, tt.frameThreads, tt.threads)
+			if got != tt.want {
+				t.Errorf("Encoder() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoderMemory(t *testing.T) {
+	tests := []struct {
+		pictureBytes uint64
+		dpbFrames    uint8
+		want         uint32
+	}{
+		{MiB, 5, 5},
+		{3110400, 5, 15},
+		{3110400, 1, 3},
+		{0, 16, 0},
+	}
+	for _, tt := range tests {
+		if got := DecoderMemory(tt.pictureBytes, tt.dpbFrames); got != tt.want {
+			t.Errorf("DecoderMemory(%d, %d) = %d, want %d", tt.pictureBytes, tt.dpbFrames, got, tt.want)
+		}
+	}
+}
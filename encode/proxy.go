@@ -0,0 +1,42 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// ProxySize defines the size of proxy files relative to the master, which are used in place of
+// the master for editing in NLEs.
+type ProxySize uint8
+
+const (
+	ProxyHalf ProxySize = iota
+	ProxyQuarter
+)
+
+// ProxyGOPMode is the GOP structure of proxy files, which is friendly to scrubbing.
+const ProxyGOPMode = EditGOP
+
+// Return the scale of the proxy relative to the master.
+func (p ProxySize) Ratio() video.Ratio {
+	if p == ProxyQuarter {
+		return video.NewRatio(1, 4)
+	}
+	return video.NewRatio(1, 2)
+}
+
+// Return the resolution of the proxy of specified master resolution, with even dimensions.
+func (p ProxySize) Resolution(master *video.Resolution) *video.Resolution {
+	return master.Scale(p.Ratio(), video.Mod2)
+}
+
+// Return the label appended to the master profile name, e.g. "proxy-half".
+func (p ProxySize) Label() string {
+	if p == ProxyQuarter {
+		return "proxy-quarter"
+	}
+	return "proxy-half"
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestProxySize(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       ProxySize
+		master     video.Resolution
+		wantWidth  uint32
+		wantHeight uint32
+		wantLabel  string
+	}{
+		{"1080p half", ProxyHalf, video.Resolution{Width: 1920, Height: 1080}, 960, 540, "proxy-half"},
+		{"1080p quarter", ProxyQuarter, video.Resolution{Width: 1920, Height: 1080}, 480, 270, "proxy-quarter"},
+		{"1440p quarter", ProxyQuarter, video.Resolution{Width: 2560, Height: 1440}, 640, 360, "proxy-quarter"},
+		{"2160p quarter", ProxyQuarter, video.Resolution{Width: 3840, Height: 2160}, 960, 540, "proxy-quarter"},
+		{"odd height after halving", ProxyHalf, video.Resolution{Width: 720, Height: 486}, 360, 244, "proxy-half"},
+		{"odd width after quartering", ProxyQuarter, video.Resolution{Width: 1998, Height: 1080}, 500, 270, "proxy-quarter"},
+		{"odd sizes after quartering", ProxyQuarter, video.Resolution{Width: 1364, Height: 766}, 342, 192, "proxy-quarter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.size.Resolution(&tt.master)
+			if got.Width != tt.wantWidth || got.Height != tt.wantHeight {
+				t.Errorf("Resolution() = %dx%d, want %dx%d", got.Width, got.Height, tt.wantWidth, tt.wantHeight)
+			}
+			if got.Width%2 != 0 || got.Height%2 != 0 {
+				t.Errorf("Resolution() = %dx%d, want even dimensions", got.Width, got.Height)
+			}
+			if label := tt.size.Label(); label != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestProxyGOPMode(t *testing.T) {
+	if ProxyGOPMode != EditGOP {
+		t.Errorf("ProxyGOPMode = %d, want EditGOP", ProxyGOPMode)
+	}
+	if !ProxyGOPMode.IsEdit() || ProxyGOPMode.IsIntraOnly() {
+		t.Errorf("ProxyGOPMode must be an edit mode with inter frames")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QualityTier is a named range of rate factors of a codec.
+// RateFactor is the nominal rate factor of the tier, RateFactorMax is the highest rate factor in the tier.
+type QualityTier struct {
+	Name          string
+	RateFactor    float64
+	RateFactorMax float64
+}
+
+// QualityLadder contains the quality tiers of a codec, ordered from the best quality.
+// It names profiles and selects encoder heuristics, so both always agree on tier boundaries.
+type QualityLadder []QualityTier
+
+// Return the position of the tier containing the rate factor, 0 is the best quality.
+// Rate factors above the last tier belong to the last tier.
+func (l QualityLadder) Index(rateFactor float64) int {
+	for i, tier := range l {
+		if rateFactor <= tier.RateFactorMax {
+			return i
+		}
+	}
+	return len(l) - 1
+}
+
+// Return the tier containing the rate factor.
+func (l QualityLadder) Tier(rateFactor float64) QualityTier {
+	return l[l.Index(rateFactor)]
+}
+
+// Return the label used in profile name, which is the tier name for the nominal rate factor of the tier,
+// or the tier name followed by the rate factor otherwise, e.g. "H" for 20 and "H19.5" for 19.5.
+func (l QualityLadder) Label(rateFactor float64) string {
+	tier := l.Tier(rateFactor)
+	if rateFactor == tier.RateFactor {
+		return tier.Name
+	}
+	return tier.Name + strconv.FormatFloat(rateFactor, 'f', -1, 64)
+}
+
+// Return the quality ladder parsed from comma separated tiers in form name:nominal:max,
+// e.g. "X:16:17,H:20:22,L:24:51". Tiers are sorted by maximum rate factor.
+// Tier names must be unique, and the nominal rate factor of a tier must be within the tier,
+// which starts above the maximum rate factor of the previous tier.
+func ParseQualityLadder(value string) (QualityLadder, error) {
+	ladder := QualityLadder{}
+	names := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		fields := strings.Split(strings.TrimSpace(item), ":")
+		if len(fields) != 3 || fields[0] == "" {
+			return nil, fmt.Errorf("invalid quality tier %q, expected name:nominal:max", item)
+		}
+		if names[fields[0]] {
+			return nil, fmt.Errorf("duplicate quality tier name %q", fields[0])
+		}
+		names[fields[0]] = true
+		rateFactor, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nominal rate factor of quality tier %q: %w", item, err)
+		}
+		rateFactorMax, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maximum rate factor of quality tier %q: %w", item, err)
+		}
+		if rateFactor > rateFactorMax {
+			return nil, fmt.Errorf("nominal rate factor of quality tier %q exceeds its maximum", item)
+		}
+		ladder = append(ladder, QualityTier{Name: fields[0], RateFactor: rateFactor, RateFactorMax: rateFactorMax})
+	}
+	sort.SliceStable(ladder, func(i, j int) bool { return ladder[i].RateFactorMax < ladder[j].RateFactorMax })
+	for i := 1; i < len(ladder); i++ {
+		if ladder[i].RateFactor <= ladder[i-1].RateFactorMax {
+			return nil, fmt.Errorf("nominal rate factor %v of quality tier %q is within quality tier %q",
+				ladder[i].RateFactor, ladder[i].Name, ladder[i-1].Name)
+		}
+	}
+	return ladder, nil
+}
+
+// Return rate factors parsed from a comma separated list, e.g. "24,20,18.5".
+func ParseRateFactors(value string) ([]float64, error) {
+	rateFactors := []float64{}
+	for _, item := range strings.Split(value, ",") {
+		rateFactor, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate factor %q: %w", item, err)
+		}
+		rateFactors = append(rateFactors, rateFactor)
+	}
+	return rateFactors, nil
+}
+
+// EquivalencePoint is a calibrated pair of a rate factor of x264, which is the reference scale,
+// and the rate factor of a codec giving the same visual quality.
+type EquivalencePoint struct {
+	Reference  float64
+	RateFactor float64
+}
+
+// Equivalence maps rate factors of a codec to and from the reference scale of x264
+// by linear interpolation between calibrated points ordered by reference rate factor.
+// Beyond the calibrated range, the offset of the nearest point is kept. Empty Equivalence is identity.
+type Equivalence []EquivalencePoint
+
+// Return the rate factor of the codec equivalent to the reference rate factor.
+func (e Equivalence) FromReference(reference float64) float64 {
+	return interpolate(e, reference, func(p EquivalencePoint) (float64, float64) { return p.Reference, p.RateFactor })
+}
+
+// Return the reference rate factor equivalent to the rate factor of the codec.
+func (e Equivalence) ToReference(rateFactor float64) float64 {
+	return interpolate(e, rateFactor, func(p EquivalencePoint) (float64, float64) { return p.RateFactor, p.Reference })
+}
+
+// Return the value mapped from x by linear interpolation between points, where pair returns the x and y of a point.
+func interpolate(points Equivalence, x float64, pair func(EquivalencePoint) (float64, float64)) float64 {
+	if len(points) == 0 {
+		return x
+	}
+	x0, y0 := pair(points[0])
+	if x <= x0 {
+		return x + y0 - x0
+	}
+	for _, point := range points[1:] {
+		x1, y1 := pair(point)
+		if x <= x1 {
+			return y0 + (x-x0)*(y1-y0)/(x1-x0)
+		}
+		x0, y0 = x1, y1
+	}
+	return x + y0 - x0
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQualityLadder(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    QualityLadder
+		wantErr bool
+	}{
+		{"default", "X:16:17,H:20:22,L:24:51", QualityLadder{{"X", 16, 17}, {"H", 20, 22}, {"L", 24, 51}}, false},
+		{"unordered", "L:24:51, X:16:17, H:20:22", QualityLadder{{"X", 16, 17}, {"H", 20, 22}, {"L", 24, 51}}, false},
+		{"single tier", "Q:23:51", QualityLadder{{"Q", 23, 51}}, false},
+		{"fractional", "X:16.5:17.5,H:18:22", QualityLadder{{"X", 16.5, 17.5}, {"H", 18, 22}}, false},
+		{"nominal at maximum", "X:17:17,H:22:22", QualityLadder{{"X", 17, 17}, {"H", 22, 22}}, false},
+		{"duplicate name", "X:16:17,X:20:22", nil, true},
+		{"nominal above maximum", "X:18:17", nil, true},
+		{"nominal in previous tier", "X:16:17,H:17:22", nil, true},
+		{"nominal below previous tier", "X:16:20,H:18:22", nil, true},
+		{"same maximum", "X:16:22,H:22:22", nil, true},
+		{"missing field", "X:16", nil, true},
+		{"empty name", ":16:17", nil, true},
+		{"invalid nominal", "X:a:17", nil, true},
+		{"invalid maximum", "X:16:b", nil, true},
+		{"empty", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQualityLadder(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQualityLadder(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseQualityLadder(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityLadder(t *testing.T) {
+	ladder := QualityLadder{{"X", 16, 17}, {"H", 20, 22}, {"L", 24, 51}}
+	tests := []struct {
+		rateFactor float64
+		wantIndex  int
+		wantLabel  string
+	}{
+		{0, 0, "X0"},
+		{16, 0, "X"},
+		{17, 0, "X17"},
+		{17.5, 1, "H17.5"},
+		{20, 1, "H"},
+		{22, 1, "H22"},
+		{24, 2, "L"},
+		{51, 2, "L51"},
+		{60, 2, "L60"},
+	}
+	for _, tt := range tests {
+		if got := ladder.Index(tt.rateFactor); got != tt.wantIndex {
+			t.Errorf("Index(%v) = %d, want %d", tt.rateFactor, got, tt.wantIndex)
+		}
+		if got := ladder.Label(tt.rateFactor); got != tt.wantLabel {
+			t.Errorf("Label(%v) = %q, want %q", tt.rateFactor, got, tt.wantLabel)
+		}
+	}
+}
+
+func TestParseRateFactors(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []float64
+		wantErr bool
+	}{
+		{"24,20", []float64{24, 20}, false},
+		{"24, 18.5", []float64{24, 18.5}, false},
+		{"24,", nil, true},
+		{"high", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRateFactors(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseRateFactors(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRateFactors(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEquivalence(t *testing.T) {
+	equivalence := Equivalence{{16, 17}, {20, 21}, {24, 25}, {26, 28}}
+	tests := []struct {
+		name       string
+		reference  float64
+		rateFactor float64
+	}{
+		{"below range keeps offset", 10, 11},
+		{"first point", 16, 17},
+		{"between points", 18, 19},
+		{"calibrated point", 20, 21},
+		{"steeper segment", 25, 26.5},
+		{"last point", 26, 28},
+		{"above range keeps offset", 30, 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := equivalence.FromReference(tt.reference); got != tt.rateFactor {
+				t.Errorf("FromReference(%v) = %v, want %v", tt.reference, got, tt.rateFactor)
+			}
+			if got := equivalence.ToReference(tt.rateFactor); got != tt.reference {
+				t.Errorf("ToReference(%v) = %v, want %v", tt.rateFactor, got, tt.reference)
+			}
+		})
+	}
+	if got := (Equivalence{}).FromReference(23.5); got != 23.5 {
+		t.Errorf("empty Equivalence FromReference(23.5) = %v, want 23.5", got)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateMode defines how the encoder distributes bits.
+type RateMode uint8
+
+const (
+	// CRF keeps a constant quality by rate factor, bitrate follows the content.
+	CRF RateMode = iota
+	// CappedCRF keeps a constant quality by rate factor, with the bitrate limited by VBV
+	// and the rate factor raised by VBV limited to CRFMaxOffset above.
+	CappedCRF
+	// ABR targets an average bitrate in a single pass.
+	ABR
+	// TwoPassABR targets an average bitrate in two passes, which distributes bits better than ABR.
+	TwoPassABR
+	// TargetSize targets a file size for a duration in two passes.
+	TargetSize
+	// CBR keeps a constant bitrate, whose VBV maximum bitrate equals the average bitrate.
+	CBR
+)
+
+// Return the number of encoding passes.
+func (m RateMode) Passes() uint8 {
+	if m == TwoPassABR || m == TargetSize {
+		return 2
+	}
+	return 1
+}
+
+// Return true if the mode targets a bitrate instead of a rate factor.
+func (m RateMode) IsBitRate() bool {
+	return m == ABR || m == TwoPassABR || m == TargetSize || m == CBR
+}
+
+// Return true if the mode limits the bitrate by VBV maximum bitrate and buffer size.
+func (m RateMode) HasVBV() bool {
+	return m == CappedCRF || m == CBR
+}
+
+// Return the short label used in profile name, e.g. "2pass" for TwoPassABR, or empty for CRF.
+func (m RateMode) Label() string {
+	switch m {
+	case CappedCRF:
+		return "ccrf"
+	case ABR:
+		return "abr"
+	case TwoPassABR:
+		return "2pass"
+	case TargetSize:
+		return "size"
+	case CBR:
+		return "cbr"
+	}
+	return ""
+}
+
+// CRFMaxOffset is how far VBV may raise the rate factor of capped CRF above the requested rate factor,
+// which keeps the quality of complex scenes from collapsing at the cost of possible VBV underflow.
+const CRFMaxOffset = 5
+
+// RateControl selects the rate control mode and its bitrate target.
+// BitRate is the average bitrate of ABR, TwoPassABR and CBR, or the maximum bitrate of CappedCRF in kbps,
+// the maximum bitrate of the level is used when zero.
+// TargetSize in MiB and Duration define the average bitrate of TargetSize mode.
+type RateControl struct {
+	Mode       RateMode
+	BitRate    uint32
+	TargetSize uint32
+	Duration   time.Duration
+}
+
+// Return the average bitrate in kbps of a file of sizeMiB MiB lasting duration, or 0 if duration is shorter than 1 ms.
+func TargetBitRate(sizeMiB uint32, duration time.Duration) uint32 {
+	milliseconds := duration.Milliseconds()
+	if milliseconds <= 0 {
+		return 0
+	}
+	// bits per millisecond equals kbps
+	return uint32(uint64(sizeMiB) * 1024 * 1024 * 8 / uint64(milliseconds))
+}
+
+// Return the bitrate in kbps requested for the mode, which determines the minimum level,
+// or 0 if the bitrate is derived from the level.
+func (c RateControl) RequestedBitRate() uint32 {
+	switch c.Mode {
+	case CRF:
+		return 0
+	case TargetSize:
+		return TargetBitRate(c.TargetSize, c.Duration)
+	}
+	return c.BitRate
+}
+
+// Return the average bitrate in kbps of bitrate modes, which is levelBitRate if not requested,
+// or 0 for rate factor modes.
+func (c RateControl) AverageBitRate(levelBitRate uint32) uint32 {
+	if !c.Mode.IsBitRate() {
+		return 0
+	}
+	if bitRate := c.RequestedBitRate(); bitRate > 0 {
+		return bitRate
+	}
+	return levelBitRate
+}
+
+// Return the VBV maximum bitrate and buffer size in kbps of modes with VBV, or zeros otherwise.
+// The maximum bitrate is the requested bitrate or levelBitRate, and the buffer holds
+// the same duration at the maximum bitrate as levelBufferSize does at levelBitRate.
+func (c RateControl) VBV(levelBitRate, levelBufferSize uint32) (maxBitRate, bufferSize uint32) {
+	if !c.Mode.HasVBV() || levelBitRate == 0 {
+		return 0, 0
+	}
+	maxBitRate = levelBitRate
+	if bitRate := c.RequestedBitRate(); bitRate > 0 {
+		maxBitRate = bitRate
+	}
+	bufferSize = uint32(uint64(maxBitRate) * uint64(levelBufferSize) / uint64(levelBitRate))
+	return maxBitRate, bufferSize
+}
+
+// Return the rate control with CRF mode capped by VBV at the maximum of the level, other modes are unchanged.
+func (c RateControl) Capped() RateControl {
+	if c.Mode == CRF {
+		c.Mode = CappedCRF
+	}
+	return c
+}
+
+// Return the label used in profile name, e.g. "2pass8000" for TwoPassABR at 8000 kbps,
+// "size4096-120min" for 4096 MiB in 2 hours, or empty for CRF.
+func (c RateControl) Label() string {
+	switch c.Mode {
+	case CRF:
+		return ""
+	case TargetSize:
+		return fmt.Sprintf("%s%d-%dmin", c.Mode.Label(), c.TargetSize, int(c.Duration.Minutes()))
+	}
+	if c.BitRate > 0 {
+		return fmt.Sprintf("%s%d", c.Mode.Label(), c.BitRate)
+	}
+	return c.Mode.Label()
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTargetBitRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizeMiB  uint32
+		duration time.Duration
+		want     uint32
+	}{
+		{"2 hours", 4096, 2 * time.Hour, 4772},
+		{"1 second", 1, time.Second, 8388},
+		{"half second", 1, 500 * time.Millisecond, 16777},
+		{"1 millisecond", 1, time.Millisecond, 8388608},
+		{"sub millisecond", 1, time.Microsecond, 0},
+		{"zero", 4096, 0, 0},
+		{"negative", 4096, -time.Second, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TargetBitRate(tt.sizeMiB, tt.duration); got != tt.want {
+				t.Errorf("TargetBitRate(%d, %v) = %d, want %d", tt.sizeMiB, tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateControlBitRates(t *testing.T) {
+	tests := []struct {
+		name           string
+		rateControl    RateControl
+		wantRequested  uint32
+		wantAverage    uint32
+		wantMaxBitRate uint32
+		wantBufferSize uint32
+		wantLabel      string
+	}{
+		{"crf", RateControl{Mode: CRF}, 0, 0, 0, 0, ""},
+		{"capped crf at level", RateControl{Mode: CappedCRF}, 0, 0, 20000, 25000, "ccrf"},
+		{"capped crf at bitrate", RateControl{Mode: CappedCRF, BitRate: 8000}, 8000, 0, 8000, 10000, "ccrf8000"},
+		{"abr at level", RateControl{Mode: ABR}, 0, 20000, 0, 0, "abr"},
+		{"abr at bitrate", RateControl{Mode: ABR, BitRate: 8000}, 8000, 8000, 0, 0, "abr8000"},
+		{"2 pass", RateControl{Mode: TwoPassABR, BitRate: 8000}, 8000, 8000, 0, 0, "2pass8000"},
+		{"target size", RateControl{Mode: TargetSize, TargetSize: 4096, Duration: 2 * time.Hour}, 4772, 4772, 0, 0, "size4096-120min"},
+		{"target size without duration", RateControl{Mode: TargetSize, TargetSize: 4096}, 0, 20000, 0, 0, "size4096-0min"},
+		{"cbr", RateControl{Mode: CBR, BitRate: 25000}, 25000, 25000, 25000, 31250, "cbr25000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rateControl.RequestedBitRate(); got != tt.wantRequested {
+				t.Errorf("RequestedBitRate() = %d, want %d", got, tt.wantRequested)
+			}
+			if got := tt.rateControl.AverageBitRate(20000); got != tt.wantAverage {
+				t.Errorf("AverageBitRate(20000) = %d, want %d", got, tt.wantAverage)
+			}
+			maxBitRate, bufferSize := tt.rateControl.VBV(20000, 25000)
+			if maxBitRate != tt.wantMaxBitRate || bufferSize != tt.wantBufferSize {
+				t.Errorf("VBV(20000, 25000) = %d, %d, want %d, %d", maxBitRate, bufferSize, tt.wantMaxBitRate, tt.wantBufferSize)
+			}
+			if got := tt.rateControl.Label(); got != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestRateControlVBVWithoutLevel(t *testing.T) {
+	maxBitRate, bufferSize := RateControl{Mode: CBR, BitRate: 8000}.VBV(0, 0)
+	if maxBitRate != 0 || bufferSize != 0 {
+		t.Errorf("VBV(0, 0) = %d, %d, want 0, 0", maxBitRate, bufferSize)
+	}
+}
+
+func TestRateModePasses(t *testing.T) {
+	tests := []struct {
+		mode          RateMode
+		wantPasses    uint8
+		wantIsBitRate bool
+		wantHasVBV    bool
+	}{
+		{CRF, 1, false, false},
+		{CappedCRF, 1, false, true},
+		{ABR, 1, true, false},
+		{TwoPassABR, 2, true, false},
+		{TargetSize, 2, true, false},
+		{CBR, 1, true, true},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.Passes(); got != tt.wantPasses {
+			t.Errorf("RateMode(%d).Passes() = %d, want %d", tt.mode, got, tt.wantPasses)
+		}
+		if got := tt.mode.IsBitRate(); got != tt.wantIsBitRate {
+			t.Errorf("RateMode(%d).IsBitRate() = %v, want %v", tt.mode, got, tt.wantIsBitRate)
+		}
+		if got := tt.mode.HasVBV(); got != tt.wantHasVBV {
+			t.Errorf("RateMode(%d).HasVBV() = %v, want %v", tt.mode, got, tt.wantHasVBV)
+		}
+	}
+}
+
+func TestRateControlCapped(t *testing.T) {
+	tests := []struct {
+		name        string
+		rateControl RateControl
+		want        RateControl
+	}{
+		{"crf", RateControl{Mode: CRF}, RateControl{Mode: CappedCRF}},
+		{"capped crf", RateControl{Mode: CappedCRF, BitRate: 8000}, RateControl{Mode: CappedCRF, BitRate: 8000}},
+		{"abr", RateControl{Mode: ABR, BitRate: 8000}, RateControl{Mode: ABR, BitRate: 8000}},
+		{"cbr", RateControl{Mode: CBR, BitRate: 8000}, RateControl{Mode: CBR, BitRate: 8000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rateControl.Capped(); got != tt.want {
+				t.Errorf("Capped() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
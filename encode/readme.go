@@ -0,0 +1,11 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package encode defines encoding options shared by all codecs, which are not properties of the video itself.
+*/
+
+package encode
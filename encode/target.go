@@ -0,0 +1,39 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QualityTarget is an abstract quality target shared by all codecs.
+// Reference is the rate factor of x264 reaching the target, which is mapped to other codecs by their Equivalence.
+type QualityTarget struct {
+	Name      string
+	Reference float64
+}
+
+// QualityTargets are the known quality targets, ordered from the best quality.
+var QualityTargets = []QualityTarget{
+	{Name: "transparent", Reference: 16},
+	{Name: "high", Reference: 20},
+	{Name: "standard", Reference: 24},
+	{Name: "archive-small", Reference: 26},
+}
+
+// Return the quality target of the name, or error listing all known targets.
+func FindQualityTarget(name string) (QualityTarget, error) {
+	names := []string{}
+	for _, target := range QualityTargets {
+		if target.Name == name {
+			return target, nil
+		}
+		names = append(names, target.Name)
+	}
+	return QualityTarget{}, fmt.Errorf("unknown quality target %q, expected one of %s", name, strings.Join(names, ", "))
+}
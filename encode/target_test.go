@@ -0,0 +1,33 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package encode
+
+import "testing"
+
+func TestFindQualityTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    QualityTarget
+		wantErr bool
+	}{
+		{"transparent", QualityTarget{"transparent", 16}, false},
+		{"archive-small", QualityTarget{"archive-small", 26}, false},
+		{"High", QualityTarget{}, true},
+		{"", QualityTarget{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindQualityTarget(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindQualityTarget(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FindQualityTarget(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package ffmpeg
+
+import (
+	"strings"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/cmdline"
+)
+
+// Arguments holds options of an FFmpeg video encoder.
+// Options are passed to FFmpeg directly while Params are passed to the encoder library
+// through the private option of the encoder, e.g. -x264-params.
+type Arguments struct {
+	Encoder    string
+	ParamsName string
+	Options    []cmdline.Option
+	Params     []cmdline.Option
+}
+
+// Return new Arguments for specified encoder and its private params option.
+func New(encoder, paramsName string) *Arguments {
+	return &Arguments{
+		Encoder:    encoder,
+		ParamsName: paramsName,
+		Options:    []cmdline.Option{},
+		Params:     []cmdline.Option{},
+	}
+}
+
+// Append an FFmpeg option, e.g. -crf 20.
+func (a *Arguments) Set(name, value string) *Arguments {
+	a.Options = append(a.Options, cmdline.Option{Name: name, Value: value})
+	return a
+}
+
+// Append encoder library params, e.g. ref=5.
+func (a *Arguments) Param(params ...cmdline.Option) *Arguments {
+	a.Params = append(a.Params, params...)
+	return a
+}
+
+// paramEscaper escapes the characters FFmpeg treats specially when it tokenizes a param value:
+// the backslash escape itself, the single quote and the colon separating params.
+var paramEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, ":", `\:`)
+
+// Return encoder library params joined in key=value:key=value form.
+// Backslashes, quotes and colons inside values are escaped since FFmpeg parses the params as a dictionary.
+func (a *Arguments) ParamString() string {
+	params := make([]string, 0, len(a.Params))
+	for _, param := range a.Params {
+		value := param.Value
+		if value == "" {
+			value = "1"
+		}
+		params = append(params, param.Name+"="+paramEscaper.Replace(value))
+	}
+	return strings.Join(params, ":")
+}
+
+// Return the output options of FFmpeg command line, starting with -c:v.
+func (a *Arguments) Args() []string {
+	args := []string{"-c:v", a.Encoder}
+	for _, option := range a.Options {
+		args = append(args, "-"+option.Name, option.Value)
+	}
+	if len(a.Params) > 0 {
+		args = append(args, "-"+a.ParamsName, a.ParamString())
+	}
+	return args
+}
+
+// Return the output options as a shell-quoted command fragment.
+func (a *Arguments) Fragment() string {
+	args := a.Args()
+	for i, arg := range args {
+		args[i] = cmdline.Quote(arg)
+	}
+	return strings.Join(args, " ")
+}
+
+// Return content of an ffpreset file, to be used with -fpre or -vpre.
+// Stream specifiers are removed from option names as presets are applied per stream.
+func (a *Arguments) Preset(comment string) string {
+	builder := strings.Builder{}
+	if comment != "" {
+		builder.WriteString("# " + comment + "\n")
+	}
+	for _, option := range a.Options {
+		name, _, _ := strings.Cut(option.Name, ":")
+		builder.WriteString(name + "=" + option.Value + "\n")
+	}
+	if len(a.Params) > 0 {
+		builder.WriteString(a.ParamsName + "=" + a.ParamString() + "\n")
+	}
+	return builder.String()
+}
+
+// Return FFmpeg color range name of the Hybrid VUI range value.
+func ColorRange(vuiRange string) string {
+	switch vuiRange {
+	case "full":
+		return "pc"
+	case "limited":
+		return "tv"
+	}
+	return "unknown"
+}
+
+// Return FFmpeg chroma sample location name of the VUI chroma location type.
+func ChromaLocation(chromaLocation uint8) string {
+	names := []string{"left", "center", "topleft", "top", "bottomleft", "bottom"}
+	if int(chromaLocation) < len(names) {
+		return names[chromaLocation]
+	}
+	return "unspecified"
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package ffmpeg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/cmdline"
+)
+
+func TestParamString(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []cmdline.Option
+		want   string
+	}{
+		{"none", nil, ""},
+		{"single", []cmdline.Option{{Name: "ref", Value: "5"}}, "ref=5"},
+		{"switch", []cmdline.Option{{Name: "no-sao"}}, "no-sao=1"},
+		{"several", []cmdline.Option{{Name: "ref", Value: "5"}, {Name: "bframes", Value: "8"}, {Name: "no-scenecut"}}, "ref=5:bframes=8:no-scenecut=1"},
+		{"colon in value", []cmdline.Option{{Name: "deblock", Value: "-4:-3"}, {Name: "sar", Value: "32:27"}}, `deblock=-4\:-3:sar=32\:27`},
+		{"backslash in value", []cmdline.Option{{Name: "dolby-vision-rpu", Value: `C:\rpu\title.bin`}}, `dolby-vision-rpu=C\:\\rpu\\title.bin`},
+		{"quote in value", []cmdline.Option{{Name: "dhdr10-info", Value: "director's cut.json"}}, `dhdr10-info=director\'s cut.json`},
+		{"equal sign in value", []cmdline.Option{{Name: "dolby-vision-rpu", Value: "title=a.rpu"}}, "dolby-vision-rpu=title=a.rpu"},
+		{"parentheses and comma", []cmdline.Option{{Name: "master-display", Value: "G(13250,34500)L(10000000,1)"}}, "master-display=G(13250,34500)L(10000000,1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New("libx265", "x265-params").Param(tt.params...).ParamString(); got != tt.want {
+				t.Errorf("ParamString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         *Arguments
+		wantArgs     []string
+		wantFragment string
+		wantPreset   string
+	}{
+		{
+			name:         "options and params",
+			args:         New("libx264", "x264-params").Set("profile:v", "high").Set("crf", "20").Param(cmdline.Option{Name: "ref", Value: "5"}, cmdline.Option{Name: "no-scenecut"}),
+			wantArgs:     []string{"-c:v", "libx264", "-profile:v", "high", "-crf", "20", "-x264-params", "ref=5:no-scenecut=1"},
+			wantFragment: "-c:v libx264 -profile:v high -crf 20 -x264-params ref=5:no-scenecut=1",
+			wantPreset:   "# test profile\nprofile=high\ncrf=20\nx264-params=ref=5:no-scenecut=1\n",
+		},
+		{
+			name:         "options only",
+			args:         New("libx265", "x265-params").Set("crf", "21.0"),
+			wantArgs:     []string{"-c:v", "libx265", "-crf", "21.0"},
+			wantFragment: "-c:v libx265 -crf 21.0",
+			wantPreset:   "# test profile\ncrf=21.0\n",
+		},
+		{
+			name:         "quoted params",
+			args:         New("libx265", "x265-params").Param(cmdline.Option{Name: "master-display", Value: "G(13250,34500)"}),
+			wantArgs:     []string{"-c:v", "libx265", "-x265-params", "master-display=G(13250,34500)"},
+			wantFragment: "-c:v libx265 -x265-params 'master-display=G(13250,34500)'",
+			wantPreset:   "# test profile\nx265-params=master-display=G(13250,34500)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.args.Args(); !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("Args() = %q, want %q", got, tt.wantArgs)
+			}
+			if got := tt.args.Fragment(); got != tt.wantFragment {
+				t.Errorf("Fragment() = %q, want %q", got, tt.wantFragment)
+			}
+			if got := tt.args.Preset("test profile"); got != tt.wantPreset {
+				t.Errorf("Preset() = %q, want %q", got, tt.wantPreset)
+			}
+		})
+	}
+}
+
+func TestColorRange(t *testing.T) {
+	tests := []struct {
+		vuiRange string
+		want     string
+	}{
+		{"full", "pc"},
+		{"limited", "tv"},
+		{"", "unknown"},
+		{"undef", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := ColorRange(tt.vuiRange); got != tt.want {
+			t.Errorf("ColorRange(%q) = %q, want %q", tt.vuiRange, got, tt.want)
+		}
+	}
+}
+
+func TestChromaLocation(t *testing.T) {
+	tests := []struct {
+		chromaLocation uint8
+		want           string
+	}{
+		{0, "left"},
+		{1, "center"},
+		{2, "topleft"},
+		{5, "bottom"},
+		{6, "unspecified"},
+	}
+	for _, tt := range tests {
+		if got := ChromaLocation(tt.chromaLocation); got != tt.want {
+			t.Errorf("ChromaLocation(%d) = %q, want %q", tt.chromaLocation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package ffmpeg defines video encoder options of FFmpeg
+and renders them as command fragments or preset files.
+See https://ffmpeg.org/ffmpeg-codecs.html for more details.
+*/
+package ffmpeg
@@ -0,0 +1,102 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package handbrake
+
+import "encoding/json"
+
+// Preset format version written to the file, matching HandBrake 1.6.
+const (
+	VersionMajor = 47
+	VersionMinor = 0
+	VersionMicro = 0
+)
+
+// Preset types used by HandBrake.
+const (
+	BuiltInPreset = 0
+	CustomPreset  = 1
+)
+
+// Video quality types used by HandBrake.
+const (
+	AverageBitRate  = 1
+	ConstantQuality = 2
+)
+
+// PresetFile is an importable HandBrake preset file.
+type PresetFile struct {
+	PresetList   []*Folder
+	VersionMajor int
+	VersionMinor int
+	VersionMicro int
+}
+
+// Folder groups many presets under one name.
+type Folder struct {
+	ChildrenArray []*Preset
+	Folder        bool
+	PresetName    string
+	Type          int
+}
+
+// Preset contains the video settings of a HandBrake preset.
+// Settings not defined here are filled with defaults by HandBrake on import.
+type Preset struct {
+	PresetName            string
+	PresetDescription     string
+	Type                  int
+	Default               bool
+	Folder                bool
+	PictureWidth          uint32
+	PictureHeight         uint32
+	PictureKeepRatio      bool
+	PictureUseMaximumSize bool
+	PicturePAR            string
+	PicturePARWidth       uint32
+	PicturePARHeight      uint32
+	VideoEncoder          string
+	VideoPreset           string
+	VideoTune             string
+	VideoProfile          string
+	VideoLevel            string
+	VideoOptionExtra      string
+	VideoQualityType      int
+	VideoQualitySlider    float64
+	VideoAvgBitrate       uint32
+	VideoFramerate        string
+	VideoFramerateMode    string
+	VideoMultiPass        bool
+	VideoTurboMultiPass   bool
+}
+
+// Return new PresetFile with a single folder of specified name.
+func NewPresetFile(folderName string) *PresetFile {
+	return &PresetFile{
+		PresetList: []*Folder{
+			{
+				ChildrenArray: []*Preset{},
+				Folder:        true,
+				PresetName:    folderName,
+				Type:          CustomPreset,
+			},
+		},
+		VersionMajor: VersionMajor,
+		VersionMinor: VersionMinor,
+		VersionMicro: VersionMicro,
+	}
+}
+
+// Add presets to the last folder of the file.
+func (f *PresetFile) Add(presets ...*Preset) {
+	folder := f.PresetList[len(f.PresetList)-1]
+	folder.ChildrenArray = append(folder.ChildrenArray, presets...)
+}
+
+// Return the preset file in JSON format.
+func (f *PresetFile) JSON() ([]byte, error) {
+	return json.MarshalIndent(f, "", "  ")
+}
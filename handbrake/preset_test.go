@@ -0,0 +1,86 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package handbrake
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewPresetFile(t *testing.T) {
+	file := NewPresetFile("x264")
+	if len(file.PresetList) != 1 {
+		t.Fatalf("len(PresetList) = %d, want 1", len(file.PresetList))
+	}
+	folder := file.PresetList[0]
+	if !folder.Folder || folder.PresetName != "x264" || folder.Type != CustomPreset || len(folder.ChildrenArray) != 0 {
+		t.Errorf("folder = %+v, want empty custom folder x264", folder)
+	}
+	if file.VersionMajor != VersionMajor || file.VersionMinor != VersionMinor || file.VersionMicro != VersionMicro {
+		t.Errorf("version = %d.%d.%d, want %d.%d.%d", file.VersionMajor, file.VersionMinor, file.VersionMicro, VersionMajor, VersionMinor, VersionMicro)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	file := NewPresetFile("x264")
+	file.Add(&Preset{PresetName: "a"})
+	file.Add(&Preset{PresetName: "b"}, &Preset{PresetName: "c"})
+	file.PresetList = append(file.PresetList, &Folder{Folder: true, PresetName: "x265"})
+	file.Add(&Preset{PresetName: "d"})
+
+	want := [][]string{{"a", "b", "c"}, {"d"}}
+	for i, folder := range file.PresetList {
+		names := []string{}
+		for _, preset := range folder.ChildrenArray {
+			names = append(names, preset.PresetName)
+		}
+		if len(names) != len(want[i]) {
+			t.Fatalf("folder %d presets = %q, want %q", i, names, want[i])
+		}
+		for j := range names {
+			if names[j] != want[i][j] {
+				t.Errorf("folder %d presets = %q, want %q", i, names, want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	file := NewPresetFile("x264")
+	file.Add(&Preset{PresetName: "x264 test", Type: CustomPreset, VideoEncoder: "x264", VideoQualityType: ConstantQuality, VideoQualitySlider: 20.5})
+	content, err := file.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// HandBrake matches keys exactly, so check the raw names of the decoded document
+	var decoded map[string]any
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["VersionMajor"] != float64(VersionMajor) {
+		t.Errorf("VersionMajor = %v, want %d", decoded["VersionMajor"], VersionMajor)
+	}
+	folder := decoded["PresetList"].([]any)[0].(map[string]any)
+	if folder["PresetName"] != "x264" || folder["Folder"] != true {
+		t.Errorf("folder = %v, want folder x264", folder)
+	}
+	preset := folder["ChildrenArray"].([]any)[0].(map[string]any)
+	wantPreset := map[string]any{
+		"PresetName":         "x264 test",
+		"Type":               float64(CustomPreset),
+		"VideoEncoder":       "x264",
+		"VideoQualityType":   float64(ConstantQuality),
+		"VideoQualitySlider": 20.5,
+	}
+	for key, want := range wantPreset {
+		if preset[key] != want {
+			t.Errorf("%s = %v, want %v", key, preset[key], want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package handbrake defines the preset file format of HandBrake.
+See https://handbrake.fr/docs/en/latest/technical/official-presets.html for more details.
+*/
+package handbrake
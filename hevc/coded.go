@@ -0,0 +1,22 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import "github.com/lukaz17/hybrid-profile-generator-go/video"
+
+// MinCbSize is the minimum coding block size in luma samples, which is used by x265 by default.
+const MinCbSize = 8
+
+// Return the coded size of each picture, padded to multiple of the minimum coding block size.
+// Interlaced video is coded as field pictures of half frame height, e.g. 1080i is coded as 1920x544 fields.
+func CodedSize(width, height uint32, scanType video.ScanType) (codedWidth, codedHeight uint32) {
+	pictureHeight := height
+	if scanType == video.Interlaced {
+		pictureHeight = (pictureHeight + 1) / 2
+	}
+	return video.Mod8.Pad(width), video.Mod8.Pad(pictureHeight)
+}
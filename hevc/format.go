@@ -0,0 +1,88 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"fmt"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// Return the lowest HEVC profile supporting specified bit depth and chroma format,
+// e.g. "Main10" for 10-bit 4:2:0 and "Main422-10" for 10-bit 4:2:2.
+func ProfileName(bitDepth uint8, chromaFormat video.ChromaFormat) string {
+	depth := uint8(8)
+	if bitDepth > 10 {
+		depth = 12
+	} else if bitDepth > 8 {
+		depth = 10
+	}
+	switch chromaFormat {
+	case video.Chroma422:
+		return fmt.Sprintf("Main422-%d", max(depth, 10))
+	case video.Chroma444:
+		return fmt.Sprintf("Main444-%d", depth)
+	}
+	if depth == 8 {
+		return "Main"
+	}
+	return fmt.Sprintf("Main%d", depth)
+}
+
+// Return the multiplier of level maximum bitrate for the HEVC profile (FormatCapabilityFactor).
+func BitRateMultiplier(profileName string) float64 {
+	switch profileName {
+	case "Main12":
+		return 1.5
+	case "Main422-10":
+		return 1.667
+	case "Main422-12", "Main444-8":
+		return 2
+	case "Main444-10":
+		return 2.5
+	case "Main444-12":
+		return 3
+	}
+	return 1
+}
+
+// Return the maximum bitrate in kbps of the level for the HEVC profile and tier.
+func (p *HEVCProfile) MaxBitRate(profileName string, highTier bool) uint32 {
+	bitRate := p.BitRateKBMax
+	if highTier && p.HighTierBitRateKBMax > 0 {
+		bitRate = p.HighTierBitRateKBMax
+	}
+	return uint32(float64(bitRate) * BitRateMultiplier(profileName))
+}
+
+// Return the maximum coded picture buffer size in kbits of the level for the HEVC profile and tier.
+func (p *HEVCProfile) MaxCPBSize(profileName string, highTier bool) uint32 {
+	cpbSize := p.CPBSizeKBMax
+	if highTier && p.HighTierCPBSizeKBMax > 0 {
+		cpbSize = p.HighTierCPBSizeKBMax
+	}
+	return uint32(float64(cpbSize) * BitRateMultiplier(profileName))
+}
+
+// HighTierMinLevel is the first level defining High tier, profiles use High tier from this level.
+const HighTierMinLevel = 40
+
+// Return true if profiles of the level use High tier.
+func IsHighTier(level uint8) bool {
+	return level >= HighTierMinLevel
+}
+
+// Return the lowest level from specified level whose maximum bitrate for the HEVC profile
+// is at least bitRate in kbps, or 0 if no level fits. Each level is checked with the tier it uses, see IsHighTier.
+func MinLevelByBitRate(level uint8, bitRate uint64, profileName string) uint8 {
+	for _, profile := range profiles {
+		if profile.Level >= level && uint64(profile.MaxBitRate(profileName, IsHighTier(profile.Level))) >= bitRate {
+			return profile.Level
+		}
+	}
+	return 0
+}
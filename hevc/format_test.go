@@ -0,0 +1,137 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestProfileName(t *testing.T) {
+	tests := []struct {
+		bitDepth     uint8
+		chromaFormat video.ChromaFormat
+		want         string
+	}{
+		{8, video.Chroma420, "Main"},
+		{0, video.Chroma420, "Main"},
+		{10, video.Chroma420, "Main10"},
+		{12, video.Chroma420, "Main12"},
+		{8, video.Chroma422, "Main422-10"},
+		{10, video.Chroma422, "Main422-10"},
+		{12, video.Chroma422, "Main422-12"},
+		{8, video.Chroma444, "Main444-8"},
+		{10, video.Chroma444, "Main444-10"},
+		{12, video.Chroma444, "Main444-12"},
+	}
+	for _, tt := range tests {
+		if got := ProfileName(tt.bitDepth, tt.chromaFormat); got != tt.want {
+			t.Errorf("ProfileName(%d, %s) = %q, want %q", tt.bitDepth, tt.chromaFormat.Label(), got, tt.want)
+		}
+	}
+}
+
+func TestMaxBitRate(t *testing.T) {
+	tests := []struct {
+		profileName    string
+		highTier       bool
+		wantMultiplier float64
+		wantBitRate    uint32
+	}{
+		{"Main", false, 1, 20000},
+		{"Main10", false, 1, 20000},
+		{"Main12", false, 1.5, 30000},
+		{"Main422-10", false, 1.667, 33340},
+		{"Main422-12", false, 2, 40000},
+		{"Main444-8", false, 2, 40000},
+		{"Main444-10", false, 2.5, 50000},
+		{"Main444-12", false, 3, 60000},
+		{"Main", true, 1, 50000},
+		{"Main12", true, 1.5, 75000},
+	}
+	level41 := ProfileByLevel(41)
+	for _, tt := range tests {
+		if got := BitRateMultiplier(tt.profileName); got != tt.wantMultiplier {
+			t.Errorf("BitRateMultiplier(%q) = %v, want %v", tt.profileName, got, tt.wantMultiplier)
+		}
+		if got := level41.MaxBitRate(tt.profileName, tt.highTier); got != tt.wantBitRate {
+			t.Errorf("MaxBitRate(%q, %v) = %d, want %d", tt.profileName, tt.highTier, got, tt.wantBitRate)
+		}
+	}
+}
+
+func TestMaxBitRateBelowHighTier(t *testing.T) {
+	// levels below 4 have no High tier, so High tier falls back to Main tier
+	if got := ProfileByLevel(31).MaxBitRate("Main", true); got != 10000 {
+		t.Errorf("MaxBitRate(\"Main\", true) of level 3.1 = %d, want 10000", got)
+	}
+}
+
+func TestMaxCPBSize(t *testing.T) {
+	tests := []struct {
+		level       uint8
+		profileName string
+		highTier    bool
+		want        uint32
+	}{
+		{31, "Main", false, 10000},
+		{31, "Main", true, 10000},
+		{41, "Main", false, 20000},
+		{41, "Main", true, 50000},
+		{41, "Main12", true, 75000},
+		{51, "Main10", true, 160000},
+	}
+	for _, tt := range tests {
+		if got := ProfileByLevel(tt.level).MaxCPBSize(tt.profileName, tt.highTier); got != tt.want {
+			t.Errorf("MaxCPBSize(%q, %v) of level %d = %d, want %d", tt.profileName, tt.highTier, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestMinLevelByBitRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       uint8
+		bitRate     uint64
+		profileName string
+		want        uint8
+	}{
+		{"fits starting level", 31, 10000, "Main", 31},
+		{"raised within Main tier", 30, 8000, "Main", 31},
+		{"raised into High tier", 31, 25000, "Main", 40},
+		{"High tier limit of 4", 31, 30000, "Main", 40},
+		{"above High tier limit of 4", 31, 30001, "Main", 41},
+		{"starts in High tier", 41, 60000, "Main", 50},
+		{"profile multiplier", 40, 45000, "Main12", 40},
+		{"exceeds all levels", 31, 900000, "Main", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinLevelByBitRate(tt.level, tt.bitRate, tt.profileName); got != tt.want {
+				t.Errorf("MinLevelByBitRate(%d, %d, %q) = %d, want %d", tt.level, tt.bitRate, tt.profileName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHighTier(t *testing.T) {
+	tests := []struct {
+		level uint8
+		want  bool
+	}{
+		{0, false},
+		{31, false},
+		{40, true},
+		{62, true},
+	}
+	for _, tt := range tests {
+		if got := IsHighTier(tt.level); got != tt.want {
+			t.Errorf("IsHighTier(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
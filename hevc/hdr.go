@@ -0,0 +1,120 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"fmt"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// DynamicRange defines the transfer function and HDR metadata signaled in the bitstream.
+type DynamicRange uint8
+
+const (
+	SDR DynamicRange = iota
+	HDR10
+	HDR10Plus
+	HLG
+	DolbyVision81
+)
+
+// Return true for all HDR formats, which require Main 10 profile and BT.2020 primaries.
+func (d DynamicRange) IsHDR() bool {
+	return d != SDR
+}
+
+// Return true if the transfer function is SMPTE ST 2084 (PQ), which is used by all HDR formats except HLG.
+func (d DynamicRange) IsPQ() bool {
+	return d == HDR10 || d == HDR10Plus || d == DolbyVision81
+}
+
+// Return the color description required by the HDR format, or zero for SDR.
+func (d DynamicRange) ColorDescription() video.ColorDescription {
+	switch d {
+	case HLG:
+		return video.BT2020HLG
+	case HDR10, HDR10Plus, DolbyVision81:
+		return video.BT2020PQ
+	}
+	return video.ColorDescription{}
+}
+
+// Return the short label used in profile name, e.g. "hdr10" for HDR10, or empty for SDR.
+func (d DynamicRange) Label() string {
+	switch d {
+	case HDR10:
+		return "hdr10"
+	case HDR10Plus:
+		return "hdr10p"
+	case HLG:
+		return "hlg"
+	case DolbyVision81:
+		return "dv81"
+	}
+	return ""
+}
+
+// Chromaticity is a CIE 1931 xy coordinate in increments of 0.00002.
+type Chromaticity struct {
+	X uint16
+	Y uint16
+}
+
+// MasteringDisplay describes the color volume of the mastering display as in SMPTE ST 2086.
+// Luminance is in increments of 0.0001 cd/m2.
+type MasteringDisplay struct {
+	Green        Chromaticity
+	Blue         Chromaticity
+	Red          Chromaticity
+	WhitePoint   Chromaticity
+	LuminanceMax uint32
+	LuminanceMin uint32
+}
+
+// Common mastering displays, both are 1000 cd/m2 with D65 white point.
+var (
+	DisplayP3D65 = MasteringDisplay{
+		Green:        Chromaticity{X: 13250, Y: 34500},
+		Blue:         Chromaticity{X: 7500, Y: 3000},
+		Red:          Chromaticity{X: 34000, Y: 16000},
+		WhitePoint:   Chromaticity{X: 15635, Y: 16450},
+		LuminanceMax: 10000000,
+		LuminanceMin: 1,
+	}
+	DisplayBT2020D65 = MasteringDisplay{
+		Green:        Chromaticity{X: 8500, Y: 39850},
+		Blue:         Chromaticity{X: 6550, Y: 2300},
+		Red:          Chromaticity{X: 35400, Y: 14600},
+		WhitePoint:   Chromaticity{X: 15635, Y: 16450},
+		LuminanceMax: 10000000,
+		LuminanceMin: 1,
+	}
+)
+
+// Return true if no mastering display is specified.
+func (m MasteringDisplay) IsZero() bool {
+	return m == MasteringDisplay{}
+}
+
+// Return the mastering display in x265 notation, e.g. "G(13250,34500)B(7500,3000)R(34000,16000)WP(15635,16450)L(10000000,1)",
+// or empty if no mastering display is specified.
+func (m MasteringDisplay) String() string {
+	if m.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("G(%d,%d)B(%d,%d)R(%d,%d)WP(%d,%d)L(%d,%d)",
+		m.Green.X, m.Green.Y, m.Blue.X, m.Blue.Y, m.Red.X, m.Red.Y,
+		m.WhitePoint.X, m.WhitePoint.Y, m.LuminanceMax, m.LuminanceMin)
+}
+
+// ContentLightLevel describes the brightest pixel (MaxCLL) and the brightest frame average (MaxFALL)
+// of the content in cd/m2 as in CTA-861.3.
+type ContentLightLevel struct {
+	MaxCLL  uint16
+	MaxFALL uint16
+}
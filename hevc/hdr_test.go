@@ -0,0 +1,82 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestDynamicRange(t *testing.T) {
+	tests := []struct {
+		dynamicRange DynamicRange
+		wantHDR      bool
+		wantPQ       bool
+		wantColor    video.ColorDescription
+		wantLabel    string
+	}{
+		{SDR, false, false, video.ColorDescription{}, ""},
+		{HDR10, true, true, video.BT2020PQ, "hdr10"},
+		{HDR10Plus, true, true, video.BT2020PQ, "hdr10p"},
+		{HLG, true, false, video.BT2020HLG, "hlg"},
+		{DolbyVision81, true, true, video.BT2020PQ, "dv81"},
+	}
+	for _, tt := range tests {
+		d := tt.dynamicRange
+		if d.IsHDR() != tt.wantHDR || d.IsPQ() != tt.wantPQ || d.Label() != tt.wantLabel {
+			t.Errorf("DynamicRange(%d) = %v, %v, %q, want %v, %v, %q", d, d.IsHDR(), d.IsPQ(), d.Label(), tt.wantHDR, tt.wantPQ, tt.wantLabel)
+		}
+		if got := d.ColorDescription(); got != tt.wantColor {
+			t.Errorf("DynamicRange(%d).ColorDescription() = %+v, want %+v", d, got, tt.wantColor)
+		}
+	}
+}
+
+func TestMasteringDisplayString(t *testing.T) {
+	tests := []struct {
+		name    string
+		display MasteringDisplay
+		want    string
+	}{
+		{"none", MasteringDisplay{}, ""},
+		{"P3-D65", DisplayP3D65, "G(13250,34500)B(7500,3000)R(34000,16000)WP(15635,16450)L(10000000,1)"},
+		{"BT.2020-D65", DisplayBT2020D65, "G(8500,39850)B(6550,2300)R(35400,14600)WP(15635,16450)L(10000000,1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.display.IsZero(); got != (tt.want == "") {
+				t.Errorf("IsZero() = %v, want %v", got, tt.want == "")
+			}
+			if got := tt.display.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeProfileColorDescription(t *testing.T) {
+	custom := video.ColorDescription{Primaries: "bt709", Transfer: "bt709", Matrix: "bt709", Range: "full"}
+	tests := []struct {
+		name    string
+		profile EncodeProfile
+		want    video.ColorDescription
+	}{
+		{"SD default", EncodeProfile{Width: 720, Height: 576}, video.DefaultColor(720, 576)},
+		{"HD default", EncodeProfile{Width: 1920, Height: 1080}, video.BT709},
+		{"custom", EncodeProfile{Width: 1920, Height: 1080, Color: custom}, custom},
+		{"HDR10 overrides custom", EncodeProfile{Width: 3840, Height: 2160, Color: custom, DynamicRange: HDR10}, video.BT2020PQ},
+		{"HLG", EncodeProfile{Width: 3840, Height: 2160, DynamicRange: HLG}, video.BT2020HLG},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.ColorDescription(); got != tt.want {
+				t.Errorf("ColorDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
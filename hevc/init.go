@@ -9,18 +9,18 @@ package hevc
 // init hevc package internal variables
 func init() {
 	profiles = []*HEVCProfile{
-		{Level: 10, LumaSampleRateMax: 552960, BitRateKBMax: 128},
-		{Level: 20, LumaSampleRateMax: 3686400, BitRateKBMax: 1500},
-		{Level: 21, LumaSampleRateMax: 7372800, BitRateKBMax: 3000},
-		{Level: 30, LumaSampleRateMax: 16588800, BitRateKBMax: 6000},
-		{Level: 31, LumaSampleRateMax: 33177600, BitRateKBMax: 10000},
-		{Level: 40, LumaSampleRateMax: 66846720, BitRateKBMax: 12000},
-		{Level: 41, LumaSampleRateMax: 133693440, BitRateKBMax: 20000},
-		{Level: 50, LumaSampleRateMax: 267386880, BitRateKBMax: 25000},
-		{Level: 51, LumaSampleRateMax: 534773760, BitRateKBMax: 40000},
-		{Level: 52, LumaSampleRateMax: 1069547520, BitRateKBMax: 60000},
-		{Level: 60, LumaSampleRateMax: 1069547520, BitRateKBMax: 60000},
-		{Level: 61, LumaSampleRateMax: 2139095040, BitRateKBMax: 120000},
-		{Level: 62, LumaSampleRateMax: 4278190080, BitRateKBMax: 240000},
+		{Level: 10, LumaPictureSizeMax: 36864, LumaSampleRateMax: 552960, BitRateKBMax: 128, HighTierBitRateKBMax: 0, CPBSizeKBMax: 350, HighTierCPBSizeKBMax: 0},
+		{Level: 20, LumaPictureSizeMax: 122880, LumaSampleRateMax: 3686400, BitRateKBMax: 1500, HighTierBitRateKBMax: 0, CPBSizeKBMax: 1500, HighTierCPBSizeKBMax: 0},
+		{Level: 21, LumaPictureSizeMax: 245760, LumaSampleRateMax: 7372800, BitRateKBMax: 3000, HighTierBitRateKBMax: 0, CPBSizeKBMax: 3000, HighTierCPBSizeKBMax: 0},
+		{Level: 30, LumaPictureSizeMax: 552960, LumaSampleRateMax: 16588800, BitRateKBMax: 6000, HighTierBitRateKBMax: 0, CPBSizeKBMax: 6000, HighTierCPBSizeKBMax: 0},
+		{Level: 31, LumaPictureSizeMax: 983040, LumaSampleRateMax: 33177600, BitRateKBMax: 10000, HighTierBitRateKBMax: 0, CPBSizeKBMax: 10000, HighTierCPBSizeKBMax: 0},
+		{Level: 40, LumaPictureSizeMax: 2228224, LumaSampleRateMax: 66846720, BitRateKBMax: 12000, HighTierBitRateKBMax: 30000, CPBSizeKBMax: 12000, HighTierCPBSizeKBMax: 30000},
+		{Level: 41, LumaPictureSizeMax: 2228224, LumaSampleRateMax: 133693440, BitRateKBMax: 20000, HighTierBitRateKBMax: 50000, CPBSizeKBMax: 20000, HighTierCPBSizeKBMax: 50000},
+		{Level: 50, LumaPictureSizeMax: 8912896, LumaSampleRateMax: 267386880, BitRateKBMax: 25000, HighTierBitRateKBMax: 100000, CPBSizeKBMax: 25000, HighTierCPBSizeKBMax: 100000},
+		{Level: 51, LumaPictureSizeMax: 8912896, LumaSampleRateMax: 534773760, BitRateKBMax: 40000, HighTierBitRateKBMax: 160000, CPBSizeKBMax: 40000, HighTierCPBSizeKBMax: 160000},
+		{Level: 52, LumaPictureSizeMax: 8912896, LumaSampleRateMax: 1069547520, BitRateKBMax: 60000, HighTierBitRateKBMax: 240000, CPBSizeKBMax: 60000, HighTierCPBSizeKBMax: 240000},
+		{Level: 60, LumaPictureSizeMax: 35651584, LumaSampleRateMax: 1069547520, BitRateKBMax: 60000, HighTierBitRateKBMax: 240000, CPBSizeKBMax: 60000, HighTierCPBSizeKBMax: 240000},
+		{Level: 61, LumaPictureSizeMax: 35651584, LumaSampleRateMax: 2139095040, BitRateKBMax: 120000, HighTierBitRateKBMax: 480000, CPBSizeKBMax: 120000, HighTierCPBSizeKBMax: 480000},
+		{Level: 62, LumaPictureSizeMax: 35651584, LumaSampleRateMax: 4278190080, BitRateKBMax: 240000, HighTierBitRateKBMax: 800000, CPBSizeKBMax: 240000, HighTierCPBSizeKBMax: 800000},
 	}
 }
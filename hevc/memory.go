@@ -0,0 +1,42 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// DPBFrameMax is the maximum number of pictures in the decoded picture buffer of any level.
+const DPBFrameMax = 16
+
+// Memory is the memory model of x265. Reference pictures are interpolated on demand but keep
+// padding, motion and CU data, and lookahead pictures keep a half resolution copy with its interpolated planes.
+var Memory = encode.MemoryModel{
+	ReferenceFactor: 1.4,
+	LookaheadFactor: 1.8,
+	ThreadMiB:       8,
+	BaseMiB:         64,
+}
+
+// Return the estimated memory in MiB of x265 encoding and of decoding the stream.
+// Frame threads are chosen by x265 when zero, see FrameThreads. Interlaced video is estimated as frames,
+// since two fields take the memory of one frame.
+// The decoder buffer follows x265, which holds the reference frames or the reordered B-frames
+// with 2 more pictures, whichever is larger, and the picture being decoded.
+func EstimateMemory(width, height uint32, scanType video.ScanType, bitDepth uint8, chromaFormat video.ChromaFormat, refFrames, bFrames uint8, rcLookahead uint16, frameThreads uint8, threads uint16) (encoderMiB, decoderMiB uint32) {
+	codedWidth, codedHeight := CodedSize(width, height, video.Progressive)
+	pictureBytes := encode.PictureBytes(codedWidth, codedHeight, bitDepth, chromaFormat)
+	if frameThreads == 0 {
+		frameThreads = FrameThreads(threads, height, scanType)
+	}
+	encoderMiB = Memory.Encoder(pictureBytes, refFrames, bFrames, rcLookahead, uint16(frameThreads), threads)
+	reorderFrames := min(bFrames, 2)
+	dpbFrames := min(max(refFrames, reorderFrames+2), DPBFrameMax-1) + 1
+	decoderMiB = encode.DecoderMemory(pictureBytes, dpbFrames)
+	return encoderMiB, decoderMiB
+}
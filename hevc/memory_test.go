@@ -0,0 +1,48 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestEstimateMemory(t *testing.T) {
+	tests := []struct {
+		name         string
+		bitDepth     uint8
+		refFrames    uint8
+		bFrames      uint8
+		frameThreads uint8
+		wantEncoder  uint32
+		wantDecoder  uint32
+	}{
+		{"1080p", 8, 4, 4, 4, 514, 15},
+		{"automatic frame threads", 8, 4, 4, 0, 514, 15},
+		{"10-bit", 10, 4, 4, 4, 836, 30},
+		{"DPB holds reordered B-frames", 8, 1, 4, 4, 502, 15},
+		{"DPB without B-frames", 8, 1, 0, 4, 480, 9},
+		{"DPB limited to 16 frames", 8, 16, 4, 4, 564, 48},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, decoder := EstimateMemory(1920, 1080, video.Progressive, tt.bitDepth, video.Chroma420, tt.refFrames, tt.bFrames, 50, tt.frameThreads, 16)
+			if encoder != tt.wantEncoder || decoder != tt.wantDecoder {
+				t.Errorf("EstimateMemory() = %d, %d, want %d, %d", encoder, decoder, tt.wantEncoder, tt.wantDecoder)
+			}
+		})
+	}
+}
+
+func TestEstimateMemoryGrowsWithLookahead(t *testing.T) {
+	short, _ := EstimateMemory(3840, 2160, video.Progressive, 10, video.Chroma420, 4, 12, 20, 0, 32)
+	long, _ := EstimateMemory(3840, 2160, video.Progressive, 10, video.Chroma420, 4, 12, 120, 0, 32)
+	if long <= short {
+		t.Errorf("EstimateMemory() with lookahead 120 = %d, want more than %d with lookahead 20", long, short)
+	}
+}
@@ -6,43 +6,129 @@
 
 package hevc
 
-var profiles []*HEVCProfile
-
-// RateFactor represents the Constant Rate Factor (CRF) for encoding profiles.
-type RateFactor float64
-
-const (
-	NormalQuality RateFactor = 25
-	HighQuality   RateFactor = 21
-	UltraQuality  RateFactor = 17
+import (
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
 )
 
+var profiles []*HEVCProfile
+
 // EncodeProfile contains minimum parameters for encoding video in HEVC.
+// Width and Height are the stored size, SampleAspect is treated as square pixels when zero.
+// FrameRate is the nominal frame rate, PeakFrameRate is the highest frame rate of
+// variable frame rate sources and is zero for constant frame rate.
+// ScanType and FieldOrder describe the source scan, see video.Resolution.
+// Color is the color description of SDR video, which is video.DefaultColor of the size when zero.
+// ColorFromInput lets the encoder adjust the color description of SDR video to the input, Color is then the fallback.
+// DynamicRange selects the HDR format, MasteringDisplay and ContentLight are the static HDR metadata
+// of PQ formats, and MetadataFile is the dynamic metadata of HDR10+ (JSON) or Dolby Vision (RPU).
+// BitDepth and ChromaFormat are the output format, BitDepth is 8 when zero.
+// Lossless selects lossless coding, which ignores RateFactor, and GOPMode is the GOP structure.
+// RateControl selects the rate control mode, RateFactor is ignored by bitrate modes.
+// Source identifies the profile matrix entry the profile is generated from.
 type EncodeProfile struct {
-	Name       string
-	Width      uint16
-	Height     uint16
-	FrameRate  float64
-	RateFactor RateFactor
+	Name             string
+	Width            uint32
+	Height           uint32
+	SampleAspect     video.Ratio
+	FrameRate        video.FrameRate
+	PeakFrameRate    video.FrameRate
+	ScanType         video.ScanType
+	FieldOrder       video.FieldOrder
+	Color            video.ColorDescription
+	ColorFromInput   bool
+	DynamicRange     DynamicRange
+	MasteringDisplay MasteringDisplay
+	ContentLight     ContentLightLevel
+	MetadataFile     string
+	BitDepth         uint8
+	ChromaFormat     video.ChromaFormat
+	RateFactor       RateFactor
+	Lossless         bool
+	RateControl      encode.RateControl
+	GOPMode          encode.GOPMode
+	Source           string
+}
+
+// Return true if the profile targets variable frame rate sources.
+func (p *EncodeProfile) IsVariableFrameRate() bool {
+	return !p.PeakFrameRate.IsZero() && p.PeakFrameRate != p.FrameRate
+}
+
+// Return the output bit depth, which is 8 if not defined.
+// HDR requires at least 10-bit.
+func (p *EncodeProfile) OutputBitDepth() uint8 {
+	bitDepth := p.BitDepth
+	if bitDepth == 0 {
+		bitDepth = 8
+	}
+	if p.DynamicRange.IsHDR() && bitDepth < 10 {
+		bitDepth = 10
+	}
+	return bitDepth
+}
+
+// Return the color description, which is required by the HDR format,
+// or the default of the size if not defined.
+func (p *EncodeProfile) ColorDescription() video.ColorDescription {
+	if p.DynamicRange.IsHDR() {
+		return p.DynamicRange.ColorDescription()
+	}
+	if p.Color.IsZero() {
+		return video.DefaultColor(p.Width, p.Height)
+	}
+	return p.Color
+}
+
+// Return the frame rate used to determine level,
+// which is the peak frame rate for variable frame rate sources.
+func (p *EncodeProfile) LevelFrameRate() video.FrameRate {
+	if p.IsVariableFrameRate() {
+		return p.PeakFrameRate
+	}
+	return p.FrameRate
 }
 
 // HEVCProfile contains all constraints of an HEVC Level.
+// LumaPictureSizeMax and LumaSampleRateMax are the maximum picture size and processing rate in luma samples.
+// BitRateKBMax and HighTierBitRateKBMax are the maximum bitrate of Main tier and High tier, High tier starts from level 4.
+// CPBSizeKBMax and HighTierCPBSizeKBMax are the maximum coded picture buffer size in kbits of each tier.
 type HEVCProfile struct {
-	Level             uint8
-	LumaSampleRateMax uint32
-	BitRateKBMax      uint32
+	Level                uint8
+	LumaPictureSizeMax   uint64
+	LumaSampleRateMax    uint64
+	BitRateKBMax         uint32
+	HighTierBitRateKBMax uint32
+	CPBSizeKBMax         uint32
+	HighTierCPBSizeKBMax uint32
 }
 
-// Return minimum AVC level for specified resolution and framerate.
-func MinLevel(width, height uint16, framerate float64) uint8 {
-	if width == 0 || height == 0 || framerate <= 0 {
+// Return minimum HEVC level for specified resolution, framerate and scan type.
+// Interlaced video is coded as field pictures, which have half height at double rate.
+// Picture size is checked on the coded size, and dimensions must not exceed
+// square root of 8 times the maximum picture size. Sample rate is counted on the source size.
+func MinLevel(width, height uint32, framerate video.FrameRate, scanType video.ScanType) uint8 {
+	if width == 0 || height == 0 || framerate.IsZero() {
 		return 0
 	}
-	requiredLumaSample := uint32(float64(width) * float64(height) * framerate)
+	codedWidth, codedHeight := CodedSize(width, height, scanType)
+	pictureSize := uint64(codedWidth) * uint64(codedHeight)
+	pictureHeight := uint64(height)
+	pictureRate := uint64(framerate.Num)
+	if scanType == video.Interlaced {
+		pictureHeight = (pictureHeight + 1) / 2
+		pictureRate = pictureRate * 2
+	}
+	// compare MaxLumaSr >= width * height * num / den without rounding
+	requiredLumaSample := uint64(width) * pictureHeight * pictureRate
 
 	level := uint8(0)
 	for _, profile := range profiles {
-		if profile.LumaSampleRateMax >= requiredLumaSample {
+		pictureSizeMax := profile.LumaPictureSizeMax
+		if pictureSize > pictureSizeMax || uint64(codedWidth)*uint64(codedWidth) > pictureSizeMax*8 || uint64(codedHeight)*uint64(codedHeight) > pictureSizeMax*8 {
+			continue
+		}
+		if profile.LumaSampleRateMax*uint64(framerate.Den) >= requiredLumaSample {
 			level = profile.Level
 			break
 		}
@@ -59,9 +145,13 @@ func ProfileByLevel(level uint8) *HEVCProfile {
 	for _, profile := range profiles {
 		if profile.Level == level {
 			return &HEVCProfile{
-				Level:             profile.Level,
-				LumaSampleRateMax: profile.LumaSampleRateMax,
-				BitRateKBMax:      profile.BitRateKBMax,
+				Level:                profile.Level,
+				LumaPictureSizeMax:   profile.LumaPictureSizeMax,
+				LumaSampleRateMax:    profile.LumaSampleRateMax,
+				BitRateKBMax:         profile.BitRateKBMax,
+				HighTierBitRateKBMax: profile.HighTierBitRateKBMax,
+				CPBSizeKBMax:         profile.CPBSizeKBMax,
+				HighTierCPBSizeKBMax: profile.HighTierCPBSizeKBMax,
 			}
 		}
 	}
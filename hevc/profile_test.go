@@ -0,0 +1,99 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestLevelFrameRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		frameRate     video.FrameRate
+		peakFrameRate video.FrameRate
+		wantVariable  bool
+		wantLevel     video.FrameRate
+	}{
+		{"constant", video.FPS30, video.FrameRate{}, false, video.FPS30},
+		{"peak equals nominal", video.FPS30, video.FPS30, false, video.FPS30},
+		{"nominal 30 peak 60", video.FPS30, video.FPS60, true, video.FPS60},
+		{"ntsc nominal and peak", video.FPS29970, video.FPS59940, true, video.FPS59940},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &EncodeProfile{Width: 1920, Height: 1080, FrameRate: tt.frameRate, PeakFrameRate: tt.peakFrameRate}
+			if got := profile.IsVariableFrameRate(); got != tt.wantVariable {
+				t.Errorf("IsVariableFrameRate() = %v, want %v", got, tt.wantVariable)
+			}
+			if got := profile.LevelFrameRate(); got != tt.wantLevel {
+				t.Errorf("LevelFrameRate() = %v, want %v", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestMinLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     uint32
+		height    uint32
+		frameRate video.FrameRate
+		scanType  video.ScanType
+		want      uint8
+	}{
+		{"576p25", 720, 576, video.FPS25, video.Progressive, 30},
+		{"1080p25", 1920, 1080, video.FPS25, video.Progressive, 40},
+		{"1080p30", 1920, 1080, video.FPS30, video.Progressive, 40},
+		{"1080p50", 1920, 1080, video.FPS50, video.Progressive, 41},
+		{"1080p60", 1920, 1080, video.FPS60, video.Progressive, 41},
+		{"1080p120", 1920, 1080, video.FPS120, video.Progressive, 50},
+		{"1080i25 coded as fields", 1920, 1080, video.FPS25, video.Interlaced, 40},
+		{"2160p30", 3840, 2160, video.FPS30, video.Progressive, 50},
+		{"2160p60", 3840, 2160, video.FPS60, video.Progressive, 51},
+		{"4320p30", 7680, 4320, video.FPS30, video.Progressive, 60},
+		{"4320p120", 7680, 4320, video.FPS120, video.Progressive, 62},
+		{"beyond 16-bit size", 65536, 1080, video.FPS25, video.Progressive, 0},
+		{"wide strip limited by dimension", 8192, 64, video.FPS25, video.Progressive, 50},
+		{"beyond all levels", 16384, 8640, video.FPS30, video.Progressive, 0},
+		{"zero height", 1920, 0, video.FPS25, video.Progressive, 0},
+		{"zero frame rate", 1920, 1080, video.FrameRate{}, video.Progressive, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinLevel(tt.width, tt.height, tt.frameRate, tt.scanType); got != tt.want {
+				t.Errorf("MinLevel(%d, %d, %v, %v) = %d, want %d", tt.width, tt.height, tt.frameRate, tt.scanType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodedSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      uint32
+		height     uint32
+		scanType   video.ScanType
+		wantWidth  uint32
+		wantHeight uint32
+	}{
+		{"1080p", 1920, 1080, video.Progressive, 1920, 1080},
+		{"1080i", 1920, 1080, video.Interlaced, 1920, 544},
+		{"1080psf coded as frames", 1920, 1080, video.FakeInterlaced, 1920, 1080},
+		{"cropped", 3840, 1606, video.Progressive, 3840, 1608},
+		{"single pixel", 1, 1, video.Progressive, 8, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := CodedSize(tt.width, tt.height, tt.scanType)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("CodedSize(%d, %d, %v) = %dx%d, want %dx%d", tt.width, tt.height, tt.scanType, width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import "github.com/lukaz17/hybrid-profile-generator-go/encode"
+
+// RateFactor represents the Constant Rate Factor (CRF) for encoding profiles.
+type RateFactor float64
+
+const (
+	NormalQuality  RateFactor = 25
+	HighQuality    RateFactor = 21
+	UltraQuality   RateFactor = 17
+	PreviewQuality RateFactor = 28
+)
+
+// Tiers is the quality ladder of HEVC, which names profiles and selects encoder heuristics.
+var Tiers = encode.QualityLadder{
+	{Name: "X", RateFactor: float64(UltraQuality), RateFactorMax: 19},
+	{Name: "H", RateFactor: float64(HighQuality), RateFactorMax: 24},
+	{Name: "L", RateFactor: float64(NormalQuality), RateFactorMax: 51},
+}
+
+// Equivalence maps rate factors of HEVC to the reference scale of x264, e.g. x264 CRF 20 is x265 CRF 21.
+// The gap widens at low quality, where x265 keeps more detail at the same rate factor.
+var Equivalence = encode.Equivalence{
+	{Reference: 16, RateFactor: 17},
+	{Reference: 20, RateFactor: 21},
+	{Reference: 24, RateFactor: 25},
+	{Reference: 26, RateFactor: 28},
+}
+
+// Return the quality tier of the rate factor.
+func (r RateFactor) Tier() encode.QualityTier {
+	return Tiers.Tier(float64(r))
+}
+
+// Return the position of the quality tier of the rate factor, 0 is the best quality.
+func (r RateFactor) TierIndex() int {
+	return Tiers.Index(float64(r))
+}
+
+// Return the label of the rate factor used in profile name, see encode.QualityLadder.Label.
+func (r RateFactor) Label() string {
+	return Tiers.Label(float64(r))
+}
+
+// Return the rate factor of HEVC equivalent to the rate factor of x264.
+func EquivalentRateFactor(reference float64) RateFactor {
+	return RateFactor(Equivalence.FromReference(reference))
+}
+
+// Return the rate factor of x264 equivalent to the rate factor.
+func (r RateFactor) Reference() float64 {
+	return Equivalence.ToReference(float64(r))
+}
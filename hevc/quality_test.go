@@ -0,0 +1,54 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import "testing"
+
+func TestRateFactorTier(t *testing.T) {
+	tests := []struct {
+		rateFactor RateFactor
+		wantIndex  int
+		wantLabel  string
+	}{
+		{UltraQuality, 0, "X"},
+		{19, 0, "X19"},
+		{HighQuality, 1, "H"},
+		{24, 1, "H24"},
+		{NormalQuality, 2, "L"},
+		{PreviewQuality, 2, "L28"},
+	}
+	for _, tt := range tests {
+		if got := tt.rateFactor.TierIndex(); got != tt.wantIndex {
+			t.Errorf("RateFactor(%v).TierIndex() = %d, want %d", tt.rateFactor, got, tt.wantIndex)
+		}
+		if got := tt.rateFactor.Label(); got != tt.wantLabel {
+			t.Errorf("RateFactor(%v).Label() = %q, want %q", tt.rateFactor, got, tt.wantLabel)
+		}
+	}
+}
+
+func TestEquivalentRateFactor(t *testing.T) {
+	tests := []struct {
+		reference float64
+		want      RateFactor
+	}{
+		{16, UltraQuality},
+		{20, HighQuality},
+		{24, NormalQuality},
+		{26, PreviewQuality},
+		{22, 23},
+	}
+	for _, tt := range tests {
+		got := EquivalentRateFactor(tt.reference)
+		if got != tt.want {
+			t.Errorf("EquivalentRateFactor(%v) = %v, want %v", tt.reference, got, tt.want)
+		}
+		if reference := got.Reference(); reference != tt.reference {
+			t.Errorf("RateFactor(%v).Reference() = %v, want %v", got, reference, tt.reference)
+		}
+	}
+}
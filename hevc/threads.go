@@ -0,0 +1,82 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// CTUSize is the size of the coding tree unit used by all profiles.
+const CTUSize = 64
+
+// Return the number of CTU rows of a picture of specified height, interlaced video is coded as fields.
+func CTURows(height uint32, scanType video.ScanType) uint32 {
+	if scanType == video.Interlaced {
+		height = (height + 1) / 2
+	}
+	return (height + CTUSize - 1) / CTUSize
+}
+
+// Return the maximum number of frame threads for the picture height.
+// A frame thread waits for the rows of the reference frame within the motion search range,
+// so x265 allows at most one frame thread per 2 CTU rows.
+func MaxFrameThreads(height uint32, scanType video.ScanType) uint8 {
+	frameThreads := (CTURows(height, scanType) + 1) / 2
+	if frameThreads > 255 {
+		return 255
+	}
+	return uint8(max(frameThreads, 1))
+}
+
+// Return the number of frame threads for the number of worker threads and picture height,
+// which follows the default of x265 limited by MaxFrameThreads.
+func FrameThreads(threads uint16, height uint32, scanType video.ScanType) uint8 {
+	frameThreads := uint8(1)
+	if threads >= 32 {
+		frameThreads = uint8(5)
+		if height > 2000 {
+			frameThreads = uint8(6)
+		}
+	} else if threads >= 16 {
+		frameThreads = uint8(4)
+	} else if threads >= 8 {
+		frameThreads = uint8(3)
+	} else if threads >= 4 {
+		frameThreads = uint8(2)
+	}
+	return min(frameThreads, MaxFrameThreads(height, scanType))
+}
+
+// Return the number of threads dedicated to lookahead, or 0 to share the worker threads.
+// Dedicated threads only pay off with large pools, and x265 allows at most half of the worker threads.
+func LookaheadThreads(threads uint16) uint8 {
+	if threads < 16 {
+		return 0
+	}
+	return uint8(min(threads/8, 255))
+}
+
+// Return the thread pool specification of x265 for the number of worker threads,
+// which uses as few NUMA nodes as possible, e.g. "16" or "32,32" for 64 threads on 2 nodes of 32 cores.
+func Pools(threads uint16, numaNodes uint8, coresPerNode uint16) string {
+	if numaNodes <= 1 || coresPerNode == 0 || threads <= coresPerNode {
+		return fmt.Sprint(threads)
+	}
+	nodes := min((threads+coresPerNode-1)/coresPerNode, uint16(numaNodes))
+	pools := []string{}
+	for i := uint16(0); i < uint16(numaNodes); i++ {
+		if i < nodes {
+			pools = append(pools, fmt.Sprint((threads+nodes-1-i)/nodes))
+		} else {
+			pools = append(pools, "-")
+		}
+	}
+	return strings.Join(pools, ",")
+}
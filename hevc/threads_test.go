@@ -0,0 +1,83 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hevc
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestFrameThreads(t *testing.T) {
+	tests := []struct {
+		name     string
+		threads  uint16
+		height   uint32
+		scanType video.ScanType
+		want     uint8
+	}{
+		{"single thread", 1, 1080, video.Progressive, 1},
+		{"4 threads", 4, 1080, video.Progressive, 2},
+		{"8 threads", 8, 1080, video.Progressive, 3},
+		{"16 threads", 16, 1080, video.Progressive, 4},
+		{"32 threads", 32, 1080, video.Progressive, 5},
+		{"32 threads 2160p", 32, 2160, video.Progressive, 6},
+		{"limited by CTU rows", 32, 360, video.Progressive, 3},
+		{"limited by field CTU rows", 32, 480, video.Interlaced, 2},
+		{"single CTU row", 64, 64, video.Progressive, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FrameThreads(tt.threads, tt.height, tt.scanType); got != tt.want {
+				t.Errorf("FrameThreads(%d, %d, %v) = %d, want %d", tt.threads, tt.height, tt.scanType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookaheadThreads(t *testing.T) {
+	tests := []struct {
+		threads uint16
+		want    uint8
+	}{
+		{8, 0},
+		{15, 0},
+		{16, 2},
+		{64, 8},
+		{4096, 255},
+	}
+	for _, tt := range tests {
+		if got := LookaheadThreads(tt.threads); got != tt.want {
+			t.Errorf("LookaheadThreads(%d) = %d, want %d", tt.threads, got, tt.want)
+		}
+	}
+}
+
+func TestPools(t *testing.T) {
+	tests := []struct {
+		name         string
+		threads      uint16
+		numaNodes    uint8
+		coresPerNode uint16
+		want         string
+	}{
+		{"single node", 16, 1, 16, "16"},
+		{"fits one node", 16, 2, 32, "16"},
+		{"two nodes", 64, 2, 32, "32,32"},
+		{"uneven split", 48, 2, 32, "24,24"},
+		{"odd threads", 33, 2, 32, "17,16"},
+		{"two of four nodes", 40, 4, 32, "20,20,-,-"},
+		{"unknown cores per node", 64, 2, 0, "64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pools(tt.threads, tt.numaNodes, tt.coresPerNode); got != tt.want {
+				t.Errorf("Pools(%d, %d, %d) = %q, want %q", tt.threads, tt.numaNodes, tt.coresPerNode, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package host
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Host describes the machine running the encoder.
+// LogicalCores is the number of hardware threads, which are spread evenly over NUMANodes.
+// MemoryMiB is the total memory, which is 0 if unknown.
+type Host struct {
+	LogicalCores uint16
+	NUMANodes    uint8
+	MemoryMiB    uint64
+}
+
+// Return the number of logical cores of each NUMA node.
+func (h *Host) CoresPerNode() uint16 {
+	if h.NUMANodes <= 1 {
+		return h.LogicalCores
+	}
+	return (h.LogicalCores + uint16(h.NUMANodes) - 1) / uint16(h.NUMANodes)
+}
+
+// Return the memory budget in MiB for encoding on the host, which is budgetMiB if positive,
+// or the memory of the host otherwise. Return 0, meaning no budget, if the host or its memory is unknown.
+func MemoryBudget(budgetMiB uint64, h *Host) uint64 {
+	if budgetMiB > 0 || h == nil {
+		return budgetMiB
+	}
+	return h.MemoryMiB
+}
+
+// Return the host running this program.
+// NUMA nodes and memory are read from Linux sysfs and procfs, other systems have 1 NUMA node and unknown memory.
+func Detect() *Host {
+	return &Host{
+		LogicalCores: uint16(runtime.NumCPU()),
+		NUMANodes:    detectNUMANodes(),
+		MemoryMiB:    detectMemory(),
+	}
+}
+
+// Return the host described by the JSON file at path, e.g. {"LogicalCores": 64, "NUMANodes": 2, "MemoryMiB": 262144}.
+func Load(path string) (*Host, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	host := &Host{}
+	err = json.Unmarshal(content, host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host file %s: %w", path, err)
+	}
+	if host.LogicalCores == 0 {
+		return nil, fmt.Errorf("invalid host file %s: LogicalCores must be positive", path)
+	}
+	if host.NUMANodes == 0 {
+		host.NUMANodes = 1
+	}
+	return host, nil
+}
+
+// Return the local host for "auto", or the host loaded from the file at value otherwise.
+func Resolve(value string) (*Host, error) {
+	if value == "auto" {
+		return Detect(), nil
+	}
+	return Load(value)
+}
+
+var nodePattern = regexp.MustCompile(`^node[0-9]+$`)
+
+// Return the number of NUMA nodes listed in sysfs, or 1 if unavailable.
+func detectNUMANodes() uint8 {
+	entries, err := os.ReadDir(filepath.Join("/sys", "devices", "system", "node"))
+	if err != nil {
+		return 1
+	}
+	nodes := uint8(0)
+	for _, entry := range entries {
+		if nodePattern.MatchString(entry.Name()) {
+			nodes++
+		}
+	}
+	if nodes == 0 {
+		return 1
+	}
+	return nodes
+}
+
+// Return the total memory in MiB from procfs, or 0 if unavailable.
+func detectMemory() uint64 {
+	file, err := os.Open(filepath.Join("/proc", "meminfo"))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kib, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kib / 1024
+		}
+	}
+	return 0
+}
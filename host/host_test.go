@@ -0,0 +1,99 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package host
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Host
+		wantErr bool
+	}{
+		{"server", `{"LogicalCores": 64, "NUMANodes": 2, "MemoryMiB": 262144}`, Host{64, 2, 262144}, false},
+		{"laptop without NUMA nodes", `{"LogicalCores": 8}`, Host{8, 1, 0}, false},
+		{"zero cores", `{"LogicalCores": 0, "NUMANodes": 1}`, Host{}, true},
+		{"negative cores", `{"LogicalCores": -8}`, Host{}, true},
+		{"too many cores", `{"LogicalCores": 65536}`, Host{}, true},
+		{"invalid JSON", `{"LogicalCores": 8`, Host{}, true},
+		{"empty", ``, Host{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "host.json")
+			err := os.WriteFile(path, []byte(tt.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("Load() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	auto, err := Resolve("auto")
+	if err != nil {
+		t.Fatalf("Resolve(auto) error = %v", err)
+	}
+	if auto.LogicalCores == 0 || auto.NUMANodes == 0 {
+		t.Errorf("Resolve(auto) = %+v, want positive cores and NUMA nodes", *auto)
+	}
+	_, err = Resolve(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil {
+		t.Errorf("Resolve(missing file) error = nil, want error")
+	}
+}
+
+func TestCoresPerNode(t *testing.T) {
+	tests := []struct {
+		host Host
+		want uint16
+	}{
+		{Host{LogicalCores: 8, NUMANodes: 1}, 8},
+		{Host{LogicalCores: 8}, 8},
+		{Host{LogicalCores: 64, NUMANodes: 2}, 32},
+		{Host{LogicalCores: 10, NUMANodes: 4}, 3},
+	}
+	for _, tt := range tests {
+		if got := tt.host.CoresPerNode(); got != tt.want {
+			t.Errorf("CoresPerNode(%+v) = %d, want %d", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		budgetMiB uint64
+		host      *Host
+		want      uint64
+	}{
+		{"no budget without host", 0, nil, 0},
+		{"budget without host", 8192, nil, 8192},
+		{"memory of host", 0, &Host{LogicalCores: 64, NUMANodes: 2, MemoryMiB: 262144}, 262144},
+		{"budget overrides host", 8192, &Host{LogicalCores: 64, NUMANodes: 2, MemoryMiB: 262144}, 8192},
+		{"unknown memory of host", 0, &Host{LogicalCores: 8, NUMANodes: 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MemoryBudget(tt.budgetMiB, tt.host); got != tt.want {
+				t.Errorf("MemoryBudget(%d, %+v) = %d, want %d", tt.budgetMiB, tt.host, got, tt.want)
+			}
+		})
+	}
+}
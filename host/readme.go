@@ -0,0 +1,10 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package host describes the machine running the encoder, which is detected locally or loaded from a config file.
+*/
+package host
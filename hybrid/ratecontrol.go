@@ -0,0 +1,21 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hybrid
+
+import "github.com/lukaz17/hybrid-profile-generator-go/encode"
+
+// Return the encoding type of Hybrid for the rate mode.
+// Capped CRF and CBR are set by VBV on top of CRF and 1-pass ABR, target size is 2-pass ABR with preferTargetSize.
+func EncodingType(mode encode.RateMode) string {
+	switch mode {
+	case encode.ABR, encode.CBR:
+		return "average bitrate (1-pass)"
+	case encode.TwoPassABR, encode.TargetSize:
+		return "average bitrate (2-pass)"
+	}
+	return "constant rate factor (1-pass)"
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hybrid
+
+import (
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+)
+
+func TestEncodingType(t *testing.T) {
+	tests := []struct {
+		mode encode.RateMode
+		want string
+	}{
+		{encode.CRF, "constant rate factor (1-pass)"},
+		{encode.CappedCRF, "constant rate factor (1-pass)"},
+		{encode.ABR, "average bitrate (1-pass)"},
+		{encode.CBR, "average bitrate (1-pass)"},
+		{encode.TwoPassABR, "average bitrate (2-pass)"},
+		{encode.TargetSize, "average bitrate (2-pass)"},
+	}
+	for _, tt := range tests {
+		if got := EncodingType(tt.mode); got != tt.want {
+			t.Errorf("EncodingType(%d) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package hybrid defines helpers for profile templates of Hybrid encoder.
+See https://www.selur.de/ for more details.
+*/
+package hybrid
@@ -0,0 +1,116 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hybrid
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Return names of all top-level fields referenced by the template, sorted and without duplicates.
+// Fields referenced inside range and with blocks are ignored because the dot is changed there.
+func Fields(tmpl *template.Template) []string {
+	fields := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectFields(t.Tree.Root, fields)
+		}
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cross-check placeholders of the template against exported fields of data, which must be a struct
+// or a pointer to struct. Fields tagged with `template:"-"` are not expected to be used by the template.
+// Return the fields never referenced by the template, and an error listing all placeholders
+// that do not exist in data.
+func Validate(tmpl *template.Template, data interface{}) (unused []string, err error) {
+	dataType := reflect.TypeOf(data)
+	for dataType != nil && dataType.Kind() == reflect.Pointer {
+		dataType = dataType.Elem()
+	}
+	if dataType == nil || dataType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("template %s: data must be a struct, got %v", tmpl.Name(), dataType)
+	}
+
+	referenced := map[string]bool{}
+	unknown := []string{}
+	for _, name := range Fields(tmpl) {
+		referenced[name] = true
+		if _, ok := dataType.FieldByName(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	for i := 0; i < dataType.NumField(); i++ {
+		field := dataType.Field(i)
+		if !field.IsExported() || field.Tag.Get("template") == "-" {
+			continue
+		}
+		if !referenced[field.Name] {
+			unused = append(unused, field.Name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		err = fmt.Errorf("template %s references unknown fields of %s: %s", tmpl.Name(), dataType.Name(), strings.Join(unknown, ", "))
+	}
+	return unused, err
+}
+
+// Walk the parse tree and record the first identifier of every field placeholder.
+func collectFields(node parse.Node, fields map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, fields)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, fields)
+		}
+	case *parse.ChainNode:
+		collectFields(n.Node, fields)
+	case *parse.FieldNode:
+		fields[n.Ident[0]] = true
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			fields[n.Ident[1]] = true
+		}
+	case *parse.IfNode:
+		collectFields(n.Pipe, fields)
+		collectFields(n.List, fields)
+		collectFields(n.ElseList, fields)
+	case *parse.RangeNode:
+		collectFields(n.Pipe, fields)
+		collectFields(n.ElseList, fields)
+	case *parse.WithNode:
+		collectFields(n.Pipe, fields)
+		collectFields(n.ElseList, fields)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, fields)
+	}
+}
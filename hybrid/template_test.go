@@ -0,0 +1,79 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package hybrid
+
+import (
+	"reflect"
+	"testing"
+	"text/template"
+)
+
+type testParams struct {
+	Name       string `template:"-"`
+	RateFactor float64
+	BitRate    uint32
+	Lossless   bool
+	Pools      string
+	secret     string
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"plain", `{{.RateFactor}} {{.BitRate}}`, []string{"BitRate", "RateFactor"}},
+		{"duplicates", `{{.BitRate}}{{.BitRate}}`, []string{"BitRate"}},
+		{"if else", `{{if .BitRate}}{{.BitRate}}{{else}}{{.RateFactor}}{{end}}`, []string{"BitRate", "RateFactor"}},
+		{"functions", `{{or (ne .BitRate 0) (not .Lossless)}}`, []string{"BitRate", "Lossless"}},
+		{"printf", `{{printf "%2.1f" .RateFactor}}`, []string{"RateFactor"}},
+		{"variable", `{{$.Pools}}`, []string{"Pools"}},
+		{"range body ignored", `{{range .Pools}}{{.Inner}}{{end}}`, []string{"Pools"}},
+		{"with body ignored", `{{with .Pools}}{{.Inner}}{{end}}`, []string{"Pools"}},
+		{"no fields", `<HybridData name="pools" value="1"/>`, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New(tt.name).Parse(tt.text))
+			if got := Fields(tmpl); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		data       interface{}
+		wantUnused []string
+		wantErr    bool
+	}{
+		{"all used", `{{.RateFactor}}{{.BitRate}}{{.Lossless}}{{.Pools}}`, &testParams{}, nil, false},
+		{"excluded and unexported fields", `{{.RateFactor}}{{.BitRate}}{{.Lossless}}{{.Pools}}`, testParams{}, nil, false},
+		{"unused fields", `{{.RateFactor}}{{.Pools}}`, &testParams{}, []string{"BitRate", "Lossless"}, false},
+		{"unknown field", `{{.RateFactor}}{{.BitRate}}{{.Lossless}}{{.Pools}}{{.Missing}}`, &testParams{}, nil, true},
+		{"unknown and unused fields", `{{.Missing}}`, &testParams{}, []string{"RateFactor", "BitRate", "Lossless", "Pools"}, true},
+		{"excluded field referenced", `{{.Name}}{{.RateFactor}}{{.BitRate}}{{.Lossless}}{{.Pools}}`, &testParams{}, nil, false},
+		{"not a struct", `{{.RateFactor}}`, 42, nil, true},
+		{"nil", `{{.RateFactor}}`, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New(tt.name).Parse(tt.text))
+			unused, err := Validate(tmpl, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(unused, tt.wantUnused) {
+				t.Errorf("Validate() unused = %v, want %v", unused, tt.wantUnused)
+			}
+		})
+	}
+}
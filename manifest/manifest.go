@@ -0,0 +1,188 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Manifest lists every profile produced by a generator run.
+// CreatedAt is only set by Stamp, so manifests of identical runs are identical.
+type Manifest struct {
+	Generator string
+	Format    string
+	CreatedAt *time.Time `json:",omitempty"`
+	Entries   []*Entry
+}
+
+// Entry describes a single produced profile.
+// Params holds all encode parameters computed for the profile, it must be a struct or a pointer to struct.
+type Entry struct {
+	Name     string
+	Path     string
+	Source   string
+	Template string
+	SHA256   string
+	Params   interface{}
+}
+
+// Return new empty Manifest for specified generator and output format.
+func New(generator, format string) *Manifest {
+	return &Manifest{
+		Generator: generator,
+		Format:    format,
+		Entries:   []*Entry{},
+	}
+}
+
+// Record the time the manifest is created at, in UTC.
+func (m *Manifest) Stamp(createdAt time.Time) {
+	createdAt = createdAt.UTC()
+	m.CreatedAt = &createdAt
+}
+
+// Add an entry for the profile written to path with specified content.
+func (m *Manifest) Add(name, path, source, template string, content []byte, params interface{}) *Entry {
+	entry := &Entry{
+		Name:     name,
+		Path:     path,
+		Source:   source,
+		Template: template,
+		SHA256:   Hash(content),
+		Params:   params,
+	}
+	m.Entries = append(m.Entries, entry)
+	return entry
+}
+
+// Return the manifest in JSON format.
+func (m *Manifest) JSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// Return the manifest in CSV format, one row per entry.
+// Fields of Params are flattened into columns after the entry columns,
+// except the ones having the same name as an entry column.
+func (m *Manifest) CSV() ([]byte, error) {
+	header := []string{"Name", "Path", "Source", "Template", "SHA256"}
+	paramNames := []string{}
+	if len(m.Entries) > 0 {
+		for _, name := range fieldNames(m.Entries[0].Params) {
+			if !slices.Contains(header, name) {
+				paramNames = append(paramNames, name)
+			}
+		}
+	}
+	header = append(header, paramNames...)
+
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	err := writer.Write(header)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range m.Entries {
+		record := []string{entry.Name, entry.Path, entry.Source, entry.Template, entry.SHA256}
+		record = append(record, fieldValues(entry.Params, paramNames)...)
+		err = writer.Write(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// Return SHA-256 hash of the content in hexadecimal form.
+func Hash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+// Return names of exported fields of the struct value.
+func fieldNames(value interface{}) []string {
+	valueType := reflect.TypeOf(value)
+	for valueType != nil && valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+	if valueType == nil || valueType.Kind() != reflect.Struct {
+		return []string{}
+	}
+	names := []string{}
+	for i := 0; i < valueType.NumField(); i++ {
+		if valueType.Field(i).IsExported() {
+			names = append(names, valueType.Field(i).Name)
+		}
+	}
+	return names
+}
+
+// Return values of specified fields of the struct value in text form,
+// which is the same form as JSON for values implementing encoding.TextMarshaler.
+func fieldValues(value interface{}, names []string) []string {
+	values := make([]string, len(names))
+	structValue := reflect.ValueOf(value)
+	for structValue.Kind() == reflect.Pointer {
+		if structValue.IsNil() {
+			return values
+		}
+		structValue = structValue.Elem()
+	}
+	if structValue.Kind() != reflect.Struct {
+		return values
+	}
+	for i, name := range names {
+		field := structValue.FieldByName(name)
+		if field.IsValid() {
+			values[i] = textValue(field)
+		}
+	}
+	return values
+}
+
+// Return the value in text form, using encoding.TextMarshaler when implemented.
+func textValue(value reflect.Value) string {
+	marshaler, ok := value.Interface().(encoding.TextMarshaler)
+	if !ok && value.CanAddr() {
+		marshaler, ok = value.Addr().Interface().(encoding.TextMarshaler)
+	}
+	if ok {
+		text, err := marshaler.MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// Save the manifest to disk in both JSON and CSV format.
+// File extensions are appended to specified file name.
+func (m *Manifest) Save(fileName string) error {
+	content, err := m.JSON()
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(fileName+".json", content, 0644)
+	if err != nil {
+		return err
+	}
+	content, err = m.CSV()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName+".csv", content, 0644)
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package manifest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+type testParams struct {
+	Name       string
+	RateFactor float64
+	BitRate    uint32
+	Lossless   bool
+	hidden     string
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+	for _, tt := range tests {
+		if got := Hash([]byte(tt.content)); got != tt.want {
+			t.Errorf("Hash(%q) = %s, want %s", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	m := New("x264", "hybrid")
+	params := &testParams{Name: "profile"}
+	entry := m.Add("profile", "x264 profile.xml", "generic", "presets/x264.xml", []byte("abc"), params)
+
+	want := &Entry{
+		Name:     "profile",
+		Path:     "x264 profile.xml",
+		Source:   "generic",
+		Template: "presets/x264.xml",
+		SHA256:   Hash([]byte("abc")),
+		Params:   params,
+	}
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("Add() = %+v, want %+v", entry, want)
+	}
+	if len(m.Entries) != 1 || m.Entries[0] != entry {
+		t.Errorf("Entries = %v, want the added entry", m.Entries)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []interface{}
+		want   [][]string
+	}{
+		{
+			name:   "no entry",
+			params: nil,
+			want:   [][]string{{"Name", "Path", "Source", "Template", "SHA256"}},
+		},
+		{
+			name: "struct params",
+			params: []interface{}{
+				&testParams{Name: "ignored", RateFactor: 20.5, BitRate: 8000, Lossless: true, hidden: "x"},
+				testParams{RateFactor: 18, BitRate: 0},
+			},
+			want: [][]string{
+				{"Name", "Path", "Source", "Template", "SHA256", "RateFactor", "BitRate", "Lossless"},
+				{"p0", "p0.xml", "generic", "", Hash([]byte("p0")), "20.5", "8000", "true"},
+				{"p1", "p1.xml", "generic", "", Hash([]byte("p1")), "18", "0", "false"},
+			},
+		},
+		{
+			name:   "nil and non struct params",
+			params: []interface{}{(*testParams)(nil), "text"},
+			want: [][]string{
+				{"Name", "Path", "Source", "Template", "SHA256", "RateFactor", "BitRate", "Lossless"},
+				{"p0", "p0.xml", "generic", "", Hash([]byte("p0")), "", "", ""},
+				{"p1", "p1.xml", "generic", "", Hash([]byte("p1")), "", "", ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New("x264", "hybrid")
+			for i, params := range tt.params {
+				name := fmt.Sprintf("p%d", i)
+				m.Add(name, name+".xml", "generic", "", []byte(name), params)
+			}
+			content, err := m.CSV()
+			if err != nil {
+				t.Fatalf("CSV() error = %v", err)
+			}
+			records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+			if err != nil {
+				t.Fatalf("cannot read CSV: %v", err)
+			}
+			if !reflect.DeepEqual(records, tt.want) {
+				t.Errorf("CSV() = %q, want %q", records, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	m := New("x265", "shell")
+	m.Add("profile", "x265 profile.sh", "master", "", []byte("abc"), &testParams{Name: "profile", RateFactor: 16})
+	content, err := m.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	decoded := struct {
+		Generator string
+		Format    string
+		Entries   []struct {
+			Name   string
+			SHA256 string
+			Params map[string]interface{}
+		}
+	}{}
+	err = json.Unmarshal(content, &decoded)
+	if err != nil {
+		t.Fatalf("cannot read JSON: %v", err)
+	}
+	if decoded.Generator != "x265" || decoded.Format != "shell" || len(decoded.Entries) != 1 {
+		t.Fatalf("JSON() = %s", content)
+	}
+	entry := decoded.Entries[0]
+	if entry.Name != "profile" || entry.SHA256 != Hash([]byte("abc")) || entry.Params["RateFactor"] != float64(16) {
+		t.Errorf("JSON() entry = %+v", entry)
+	}
+}
+
+func TestJSONReproducible(t *testing.T) {
+	create := func() []byte {
+		m := New("x264", "hybrid")
+		m.Add("profile", "x264 profile.xml", "generic", "presets/x264.xml", []byte("abc"), &testParams{Name: "profile", RateFactor: 20})
+		content, err := m.JSON()
+		if err != nil {
+			t.Fatalf("JSON() error = %v", err)
+		}
+		return content
+	}
+	first, second := create(), create()
+	if !bytes.Equal(first, second) {
+		t.Errorf("JSON() = %s, then %s, want identical manifests", first, second)
+	}
+	if bytes.Contains(first, []byte("CreatedAt")) {
+		t.Errorf("JSON() = %s, want no CreatedAt without Stamp", first)
+	}
+}
+
+func TestStamp(t *testing.T) {
+	m := New("x264", "hybrid")
+	m.Stamp(time.Date(2025, 3, 1, 12, 30, 0, 0, time.FixedZone("ICT", 7*60*60)))
+	content, err := m.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	if want := `"CreatedAt": "2025-03-01T05:30:00Z"`; !bytes.Contains(content, []byte(want)) {
+		t.Errorf("JSON() = %s, want %s", content, want)
+	}
+}
+
+type frameRateParams struct {
+	Name      string
+	FrameRate video.FrameRate
+	BitRate   uint32
+	Lossless  bool
+}
+
+func TestCSVMatchesJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		frameRate video.FrameRate
+		want      string
+	}{
+		{"integer", video.FPS25, "25"},
+		{"ntsc", video.FPS29970, "30000/1001"},
+		{"film", video.FPS23976, "24000/1001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New("test", "hybrid")
+			params := &frameRateParams{Name: "profile", FrameRate: tt.frameRate, BitRate: 8000, Lossless: true}
+			m.Add(params.Name, "profile.xml", "source", "", []byte("content"), params)
+
+			content, err := m.CSV()
+			if err != nil {
+				t.Fatalf("CSV() error = %v", err)
+			}
+			records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+			if err != nil {
+				t.Fatalf("cannot read CSV: %v", err)
+			}
+			header, record := records[0], records[1]
+			values := map[string]string{}
+			for i, name := range header {
+				values[name] = record[i]
+			}
+			if values["FrameRate"] != tt.want {
+				t.Errorf("CSV FrameRate = %q, want %q", values["FrameRate"], tt.want)
+			}
+			if values["BitRate"] != "8000" || values["Lossless"] != "true" {
+				t.Errorf("CSV BitRate, Lossless = %q, %q, want 8000, true", values["BitRate"], values["Lossless"])
+			}
+
+			content, err = m.JSON()
+			if err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+			decoded := struct {
+				Entries []struct{ Params struct{ FrameRate string } }
+			}{}
+			err = json.Unmarshal(content, &decoded)
+			if err != nil {
+				t.Fatalf("cannot read JSON: %v", err)
+			}
+			if got := decoded.Entries[0].Params.FrameRate; got != values["FrameRate"] {
+				t.Errorf("JSON FrameRate = %q, CSV FrameRate = %q", got, values["FrameRate"])
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/cmdline"
+	"github.com/lukaz17/hybrid-profile-generator-go/ffmpeg"
+	"github.com/tforce-io/tf-golib/opx"
+)
+
+// Create native x264 arguments equivalent to the Hybrid template for the EncodeParams.
+func createCommandLine(params *EncodeParams) *cmdline.Arguments {
+	args := cmdline.New("x264")
+	args.Add("profile", strings.ToLower(params.AVCProfile))
+	if params.BitDepth > 8 {
+		args.Add("output-depth", fmt.Sprint(params.BitDepth))
+	}
+	if params.OutputColorSpace != "i420" {
+		args.Add("output-csp", params.OutputColorSpace)
+	}
+	args.Add("level", fmt.Sprintf("%2.1f", params.AVCLevel))
+	if params.Lossless {
+		args.Add("qp", "0")
+	} else if params.BitRate > 0 {
+		args.Add("bitrate", fmt.Sprint(params.BitRate))
+	} else {
+		args.Add("crf", fmt.Sprint(params.RateFactor))
+		if params.RateFactorMax > 0 {
+			args.Add("crf-max", fmt.Sprint(params.RateFactorMax))
+		}
+	}
+	if params.VBVMaxBitRate > 0 {
+		args.Add("vbv-maxrate", fmt.Sprint(params.VBVMaxBitRate)).
+			Add("vbv-bufsize", fmt.Sprint(params.VBVBufferSize))
+	}
+	if params.NalHRD != "none" {
+		args.Add("nal-hrd", params.NalHRD)
+	}
+	args.Add("ref", fmt.Sprint(params.RefFrame)).
+		Add("bframes", fmt.Sprint(params.BFrame)).
+		Add("b-adapt", "2").
+		Add("b-pyramid", "normal").
+		Add("me", "umh").
+		Add("merange", fmt.Sprint(params.MeRange)).
+		Add("subme", "10").
+		Add("trellis", "2")
+	if params.FastDecode {
+		args.Add("weightp", "0").
+			Flag("no-weightb").
+			Flag("no-deblock").
+			Flag("no-cabac")
+	} else {
+		args.Add("weightp", "2").
+			Add("deblock", "-2:-1")
+	}
+	args.Add("chroma-qp-offset", "-3").
+		Add("keyint", fmt.Sprint(params.KeyInterval))
+	if params.KeyIntervalMin > 0 {
+		args.Add("min-keyint", fmt.Sprint(params.KeyIntervalMin))
+	}
+	if params.SceneCut == 0 {
+		args.Flag("no-scenecut")
+	}
+	args.Add("rc-lookahead", fmt.Sprint(params.RCLookahead)).
+		Add("sync-lookahead", fmt.Sprint(params.InputLookahead)).
+		Add("aq-mode", "1").
+		Add("aq-strength", fmt.Sprintf("%2.1f", params.AQStrength)).
+		Add("sar", params.SampleAspect.String()).
+		Add("colorprim", params.VUIColorPrimes).
+		Add("transfer", params.VUITransfer).
+		Add("colormatrix", params.VUIColorMatrix).
+		Add("range", ffmpeg.ColorRange(params.VUIRange)).
+		Add("chromaloc", fmt.Sprint(params.VUIChromaLocation)).
+		Add("threads", fmt.Sprint(params.ThreadCount))
+	if params.NoPsy {
+		args.Flag("no-psy")
+	}
+	if params.Interlaced {
+		args.Flag(opx.Ternary(params.BottomFieldFirst, "bff", "tff"))
+	}
+	if params.FakeInterlaced {
+		args.Flag("fake-interlaced")
+	}
+	if params.Pulldown != "off" {
+		args.Add("pulldown", params.Pulldown)
+	}
+	if params.PicStruct {
+		args.Flag("pic-struct")
+	}
+	return args
+}
+
+// Return the comment of shell snippet, which tells to run multi-pass profiles once per pass.
+func shellComment(params *EncodeParams) string {
+	if params.Passes > 1 {
+		return fmt.Sprintf("x264 %s, run %d times with --pass 1 to --pass %d", params.Name, params.Passes, params.Passes)
+	}
+	return "x264 " + params.Name
+}
+
+// Save the native arguments of EncodeParams to disk as shell snippet or JSON array.
+// Return the file name and content written, or empty file name on failure.
+func saveCommandLine(format string, params *EncodeParams) (string, []byte) {
+	args := createCommandLine(params)
+	fileName := fmt.Sprintf("x264 %s.sh", params.Name)
+	content := []byte(args.Shell(shellComment(params)))
+	if format == "json" {
+		fileName = fmt.Sprintf("x264 %s.json", params.Name)
+		json, err := args.JSON()
+		if err != nil {
+			logger.Error(err, fileName)
+			return "", nil
+		}
+		content = json
+	}
+
+	err := os.WriteFile(fileName, content, opx.Ternary(format == "json", os.FileMode(0644), os.FileMode(0755)))
+	if err != nil {
+		logger.Error(err, "cannot write to file", fileName)
+		return "", nil
+	}
+	return fileName, content
+}
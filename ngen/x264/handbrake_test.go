@@ -0,0 +1,177 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/avc"
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/handbrake"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestCreateHandBrake(t *testing.T) {
+	params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+	preset := createHandBrake(params)
+
+	if preset.PresetName != "x264 "+params.Name {
+		t.Errorf("PresetName = %q, want %q", preset.PresetName, "x264 "+params.Name)
+	}
+	if preset.Type != handbrake.CustomPreset {
+		t.Errorf("Type = %d, want %d", preset.Type, handbrake.CustomPreset)
+	}
+	if preset.PictureWidth != 1920 || preset.PictureHeight != 1080 {
+		t.Errorf("Picture = %dx%d, want 1920x1080", preset.PictureWidth, preset.PictureHeight)
+	}
+	if preset.VideoEncoder != "x264" || preset.VideoProfile != "high" {
+		t.Errorf("VideoEncoder/VideoProfile = %s/%s, want x264/high", preset.VideoEncoder, preset.VideoProfile)
+	}
+	if want := fmt.Sprintf("%2.1f", params.AVCLevel); preset.VideoLevel != want {
+		t.Errorf("VideoLevel = %q, want %q", preset.VideoLevel, want)
+	}
+	if preset.VideoQualityType != handbrake.ConstantQuality || preset.VideoQualitySlider != params.RateFactor {
+		t.Errorf("VideoQuality = %d/%v, want %d/%v", preset.VideoQualityType, preset.VideoQualitySlider, handbrake.ConstantQuality, params.RateFactor)
+	}
+	if preset.VideoFramerate != "25" || preset.VideoFramerateMode != "cfr" {
+		t.Errorf("VideoFramerate = %s %s, want 25 cfr", preset.VideoFramerate, preset.VideoFramerateMode)
+	}
+
+	// settings with HandBrake counterpart must not be repeated in advanced options
+	extra := ":" + preset.VideoOptionExtra
+	for _, name := range []string{"profile", "level", "crf", "sar", "colormatrix", "range"} {
+		if strings.Contains(extra, ":"+name+"=") {
+			t.Errorf("VideoOptionExtra contains %s: %s", name, preset.VideoOptionExtra)
+		}
+	}
+	for _, param := range []string{fmt.Sprintf("ref=%d", params.RefFrame), "deblock=-2,-1", fmt.Sprintf("keyint=%d", params.KeyInterval)} {
+		if !strings.Contains(extra, ":"+param) {
+			t.Errorf("VideoOptionExtra missing %s: %s", param, preset.VideoOptionExtra)
+		}
+	}
+}
+
+func TestCreateHandBrakeVariableFrameRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		peakFrameRate video.FrameRate
+		wantFramerate string
+		wantMode      string
+	}{
+		{"constant", video.FrameRate{}, "30", "cfr"},
+		{"nominal 30 peak 60", video.FPS60, "60", "pfr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS30, PeakFrameRate: tt.peakFrameRate, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+			preset := createHandBrake(params)
+			if preset.VideoFramerate != tt.wantFramerate || preset.VideoFramerateMode != tt.wantMode {
+				t.Errorf("VideoFramerate = %s %s, want %s %s", preset.VideoFramerate, preset.VideoFramerateMode, tt.wantFramerate, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestCreateHandBrakeSampleAspect(t *testing.T) {
+	tests := []struct {
+		name         string
+		width        uint32
+		sampleAspect video.Ratio
+		wantSAR      video.Ratio
+		wantDAR      video.Ratio
+		wantPAR      string
+	}{
+		{"undefined as square pixels", 1920, video.Ratio{}, video.SquarePixel, video.Ratio{Num: 16, Den: 9}, "off"},
+		{"square pixels", 1920, video.SquarePixel, video.SquarePixel, video.Ratio{Num: 16, Den: 9}, "off"},
+		{"HDV", 1440, video.NewRatio(4, 3), video.Ratio{Num: 4, Den: 3}, video.Ratio{Num: 16, Den: 9}, "custom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: tt.width, Height: 1080, SampleAspect: tt.sampleAspect, FrameRate: video.FPS25, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+			if params.SampleAspect != tt.wantSAR || params.DisplayAspect != tt.wantDAR {
+				t.Errorf("SampleAspect, DisplayAspect = %v, %v, want %v, %v", params.SampleAspect, params.DisplayAspect, tt.wantSAR, tt.wantDAR)
+			}
+			preset := createHandBrake(params)
+			if preset.PicturePAR != tt.wantPAR || preset.PicturePARWidth != tt.wantSAR.Num || preset.PicturePARHeight != tt.wantSAR.Den {
+				t.Errorf("PicturePAR = %s %d:%d, want %s %v", preset.PicturePAR, preset.PicturePARWidth, preset.PicturePARHeight, tt.wantPAR, tt.wantSAR)
+			}
+		})
+	}
+}
+
+func TestCreateHandBrakeFormat(t *testing.T) {
+	tests := []struct {
+		bitDepth     uint8
+		chromaFormat video.ChromaFormat
+		wantEncoder  string
+		wantProfile  string
+	}{
+		{8, video.Chroma420, "x264", "high"},
+		{10, video.Chroma420, "x264_10bit", "high10"},
+		{10, video.Chroma422, "x264_10bit", "high422"},
+	}
+	for _, tt := range tests {
+		preset := createHandBrake(createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, BitDepth: tt.bitDepth, ChromaFormat: tt.chromaFormat, RateFactor: avc.UltraQuality, ThreadCount: 16}, nil))
+		if preset.VideoEncoder != tt.wantEncoder || preset.VideoProfile != tt.wantProfile {
+			t.Errorf("%d-bit %s: VideoEncoder/VideoProfile = %s/%s, want %s/%s", tt.bitDepth, tt.chromaFormat.Label(), preset.VideoEncoder, preset.VideoProfile, tt.wantEncoder, tt.wantProfile)
+		}
+		// bit depth and chroma format are selected by the encoder and profile
+		for _, name := range []string{"output-depth", "output-csp", "input-csp"} {
+			if strings.Contains(":"+preset.VideoOptionExtra, ":"+name+"=") {
+				t.Errorf("VideoOptionExtra contains %s: %s", name, preset.VideoOptionExtra)
+			}
+		}
+	}
+}
+
+func TestCreateHandBrakeLossless(t *testing.T) {
+	preset := createHandBrake(createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, Lossless: true, GOPMode: encode.IntraOnly, ThreadCount: 16}, nil))
+	if preset.VideoQualityType != handbrake.ConstantQuality || preset.VideoQualitySlider != 0 {
+		t.Errorf("VideoQuality = %d/%v, want %d/0", preset.VideoQualityType, preset.VideoQualitySlider, handbrake.ConstantQuality)
+	}
+	if preset.VideoProfile != "high444" {
+		t.Errorf("VideoProfile = %s, want high444", preset.VideoProfile)
+	}
+	if extra := ":" + preset.VideoOptionExtra; strings.Contains(extra, ":qp=") || !strings.Contains(extra, ":no-psy=1") {
+		t.Errorf("VideoOptionExtra = %s, want no-psy without qp", preset.VideoOptionExtra)
+	}
+}
+
+func TestCreateHandBrakeRateControl(t *testing.T) {
+	tests := []struct {
+		name            string
+		rateControl     encode.RateControl
+		wantQualityType int
+		wantAvgBitrate  uint32
+		wantMultiPass   bool
+		wantDescription string
+	}{
+		{"crf", encode.RateControl{}, handbrake.ConstantQuality, 0, false, "CRF"},
+		{"abr", encode.RateControl{Mode: encode.ABR, BitRate: 8000}, handbrake.AverageBitRate, 8000, false, "1-pass 8000 kbps"},
+		{"2 pass", encode.RateControl{Mode: encode.TwoPassABR, BitRate: 8000}, handbrake.AverageBitRate, 8000, true, "2-pass 8000 kbps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset := createHandBrake(createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, RateControl: tt.rateControl, ThreadCount: 16}, nil))
+			if preset.VideoQualityType != tt.wantQualityType || preset.VideoAvgBitrate != tt.wantAvgBitrate {
+				t.Errorf("VideoQualityType/VideoAvgBitrate = %d/%d, want %d/%d", preset.VideoQualityType, preset.VideoAvgBitrate, tt.wantQualityType, tt.wantAvgBitrate)
+			}
+			if preset.VideoMultiPass != tt.wantMultiPass || preset.VideoTurboMultiPass != tt.wantMultiPass {
+				t.Errorf("VideoMultiPass/VideoTurboMultiPass = %v/%v, want %v", preset.VideoMultiPass, preset.VideoTurboMultiPass, tt.wantMultiPass)
+			}
+			if !strings.Contains(preset.PresetDescription, tt.wantDescription) {
+				t.Errorf("PresetDescription = %q, want %q", preset.PresetDescription, tt.wantDescription)
+			}
+			// bitrate is set by VideoAvgBitrate
+			if strings.Contains(":"+preset.VideoOptionExtra, ":bitrate=") {
+				t.Errorf("VideoOptionExtra contains bitrate: %s", preset.VideoOptionExtra)
+			}
+		})
+	}
+}
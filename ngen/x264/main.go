@@ -7,13 +7,21 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"os"
+	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/lukaz17/hybrid-profile-generator-go/avc"
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/host"
+	"github.com/lukaz17/hybrid-profile-generator-go/hybrid"
+	"github.com/lukaz17/hybrid-profile-generator-go/manifest"
 	"github.com/lukaz17/hybrid-profile-generator-go/video"
 	"github.com/tforce-io/tf-golib/diag"
 	"github.com/tforce-io/tf-golib/opx"
@@ -22,30 +30,113 @@ import (
 
 var logger = diag.DefaultLogger{}
 
+// Host running the generator, whose threads x264 uses when ThreadCount is 0.
+var localHost = sync.OnceValue(host.Detect)
+
+// Path of the Hybrid template, relative to working directory.
+const templateFile = "./presets/x264.xml"
+
 // EncodeParams holds the parameters for encoding profiles.
 type EncodeParams struct {
-	Name           string
-	Width          uint16
-	Height         uint16
-	FrameRate      float64
-	ThreadCount    uint8
-	RateFactor     float64
-	AVCLevel       float64
-	RefFrame       uint8
-	MeRange        uint8
-	BFrame         uint8
-	KeyInterval    uint16
-	InputLookahead uint8
-	RCLookahead    uint16
-	AQStrength     float64
+	Name              string          `template:"-"`
+	Width             uint32          `template:"-"`
+	Height            uint32          `template:"-"`
+	FrameRate         video.FrameRate `template:"-"`
+	PeakFrameRate     video.FrameRate `template:"-"`
+	SampleAspect      video.Ratio
+	DisplayAspect     video.Ratio `template:"-"`
+	VariableFrameRate bool
+	Interlaced        bool
+	BottomFieldFirst  bool
+	FakeInterlaced    bool
+	Pulldown          string
+	PicStruct         bool
+	ThreadCount       uint8
+	RateFactor        float64
+	RateFactorMax     float64
+	Lossless          bool
+	NoPsy             bool
+	EncodingType      string
+	Passes            uint8 `template:"-"`
+	BitRate           uint32
+	TargetSize        uint32
+	PreferTargetSize  bool
+	FastFirstPass     bool
+	VBVMaxBitRate     uint32
+	VBVBufferSize     uint32
+	NalHRD            string
+	AVCLevel          float64
+	RefFrame          uint8
+	MeRange           uint8
+	BFrame            uint8
+	KeyInterval       uint16
+	KeyIntervalMin    uint16
+	SceneCut          uint8
+	FastDecode        bool
+	InputLookahead    uint8
+	RCLookahead       uint16
+	AQStrength        float64
+	AVCProfile        string
+	Transform8x8      bool
+	BitDepth          uint8
+	OutputColorSpace  string
+	PixelFormat       string `template:"-"`
+	AutoFormat        bool
+	AdjustVUIToInput  bool
+	VUIColorPrimes    string
+	VUITransfer       string
+	VUIColorMatrix    string
+	VUIRange          string
+	VUIChromaLocation uint8
+	EncoderMemoryMiB  uint32 `template:"-"`
+	DecoderMemoryMiB  uint32 `template:"-"`
 }
 
 func main() {
+	format := flag.String("format", "hybrid", "output format: hybrid, shell, json, ffmpeg, ffpreset or handbrake")
+	uncapped := flag.Bool("uncapped", false, "do not cap CRF profiles by VBV and crf-max")
+	crf := flag.String("crf", fmt.Sprintf("%v,%v", avc.NormalQuality, avc.HighQuality), "comma separated rate factors of generic profiles")
+	tiers := flag.String("tiers", "", "quality tiers in form name:nominal:max, e.g. X:16:17,H:20:22,L:24:51")
+	target := flag.String("target", "", "generate only the profile matching a quality target: "+qualityTargetNames())
+	size := flag.String("size", "1920x1080", "size of the profile matching -target")
+	memoryBudget := flag.Uint64("memory-budget", 0, "skip profiles whose estimated encoder memory exceeds this budget in MiB, 0 for the memory of -host or no budget")
+	colorFromInput := flag.Bool("color-from-input", false, "let Hybrid adjust the color description of profiles to the input")
+	hostFile := flag.String("host", "", "derive threads from the encoding host: auto to detect, or path of a JSON host file")
+	manifestTime := flag.Bool("manifest-time", false, "record the generation time in the manifest, which then differs between runs")
+	frameRate := video.FPS25
+	flag.TextVar(&frameRate, "fps", video.FPS25, "frame rate of the profile matching -target, e.g. 25 or 24000/1001")
+	flag.Parse()
+
+	rateFactors, err := encode.ParseRateFactors(*crf)
+	if err != nil {
+		logger.Error(err, "invalid argument")
+		os.Exit(1)
+	}
+	if *tiers != "" {
+		avc.Tiers, err = encode.ParseQualityLadder(*tiers)
+		if err != nil {
+			logger.Error(err, "invalid argument")
+			os.Exit(1)
+		}
+	}
+	var targetHost *host.Host
+	if *hostFile != "" {
+		targetHost, err = host.Resolve(*hostFile)
+		if err != nil {
+			logger.Error(err, "invalid argument")
+			os.Exit(1)
+		}
+		logger.Infof("threads are derived from host with %d logical cores on %d NUMA nodes", targetHost.LogicalCores, targetHost.NUMANodes)
+	}
+	budget := host.MemoryBudget(*memoryBudget, targetHost)
+
 	profiles := []*avc.EncodeProfile{
-		{Name: "NTSC DVD", Width: 640, Height: 480, FrameRate: 30, RateFactor: avc.UltraQuality, ThreadCount: 16},
-		{Name: "PAL DVD", Width: 768, Height: 576, FrameRate: 25, RateFactor: avc.UltraQuality, ThreadCount: 16},
-		{Name: "NTSC-WIDE DVD", Width: 864, Height: 480, FrameRate: 30, RateFactor: avc.UltraQuality, ThreadCount: 16},
-		{Name: "PAL-WIDE DVD", Width: 1024, Height: 576, FrameRate: 25, RateFactor: avc.UltraQuality, ThreadCount: 16},
+		{Name: "NTSC DVD", Width: 720, Height: 480, SampleAspect: video.NewRatio(8, 9), FrameRate: video.FPS29970, RateFactor: avc.UltraQuality, ThreadCount: 16, Source: "named"},
+		{Name: "PAL DVD", Width: 720, Height: 576, SampleAspect: video.NewRatio(16, 15), FrameRate: video.FPS25, RateFactor: avc.UltraQuality, ThreadCount: 16, Source: "named"},
+		{Name: "NTSC-WIDE DVD", Width: 720, Height: 480, SampleAspect: video.NewRatio(32, 27), FrameRate: video.FPS29970, RateFactor: avc.UltraQuality, ThreadCount: 16, Source: "named"},
+		{Name: "PAL-WIDE DVD", Width: 720, Height: 576, SampleAspect: video.NewRatio(64, 45), FrameRate: video.FPS25, RateFactor: avc.UltraQuality, ThreadCount: 16, Source: "named"},
+		{Name: "NTSC HDV", Width: 1440, Height: 1080, SampleAspect: video.NewRatio(4, 3), FrameRate: video.FPS29970, ScanType: video.Interlaced, RateFactor: avc.UltraQuality, ThreadCount: 16, Source: "named"},
+		{Name: "PAL HDV", Width: 1440, Height: 1080, SampleAspect: video.NewRatio(4, 3), FrameRate: video.FPS25, ScanType: video.Interlaced, RateFactor: avc.UltraQuality, ThreadCount: 16, Source: "named"},
 	}
 	// Generic profiles
 	resolutions := []*video.Resolution{
@@ -56,21 +147,28 @@ func main() {
 		{Width: 1280, Height: 720},
 		{Width: 1280, Height: 960},
 		{Width: 1440, Height: 1080},
-		{Width: 1920, Height: 816},
 		{Width: 1920, Height: 1080},
 		{Width: 1920, Height: 1440},
 		{Width: 2560, Height: 1440},
 		{Width: 3840, Height: 2160},
+		{Width: 7680, Height: 4320},
+		{Width: 720, Height: 1280},
+		{Width: 1080, Height: 1920},
+		{Width: 2160, Height: 3840},
 	}
-	framerates := []float64{25, 30, 50, 60}
-	qualities := []avc.RateFactor{
-		avc.NormalQuality,
-		avc.HighQuality,
+	croppedAspects := []video.Ratio{video.Aspect239, video.Aspect235, video.Aspect200, video.Aspect185}
+	resolutions = append(resolutions, video.ResolutionsByAspect(1920, croppedAspects, video.Mod16)...)
+	resolutions = append(resolutions, video.ResolutionsByAspect(3840, croppedAspects, video.Mod16)...)
+	framerates := []video.FrameRate{video.FPS23976, video.FPS25, video.FPS29970, video.FPS30, video.FPS50, video.FPS59940, video.FPS60, video.FPS100, video.FPS120}
+	qualities := []avc.RateFactor{}
+	for _, rateFactor := range rateFactors {
+		qualities = append(qualities, avc.RateFactor(rateFactor))
 	}
 	for _, resolution := range resolutions {
 		for _, framerate := range framerates {
 			for _, quality := range qualities {
 				profile := &avc.EncodeProfile{
+					Source:      fmt.Sprintf("generic %dx%d %vfps crf%v", resolution.Width, resolution.Height, framerate, quality),
 					Width:       resolution.Width,
 					Height:      resolution.Height,
 					FrameRate:   framerate,
@@ -87,10 +185,11 @@ func main() {
 		{Width: 2560, Height: 1440},
 		{Width: 3840, Height: 2160},
 	}
-	mfFramerates := []float64{25, 30}
+	mfFramerates := []video.FrameRate{video.FPS23976, video.FPS25, video.FPS30}
 	for _, resolution := range mfResolutions {
 		for _, framerate := range mfFramerates {
 			profile := &avc.EncodeProfile{
+				Source:     fmt.Sprintf("master %dx%d %vfps", resolution.Width, resolution.Height, framerate),
 				Width:      resolution.Width,
 				Height:     resolution.Height,
 				FrameRate:  framerate,
@@ -100,85 +199,523 @@ func main() {
 		}
 	}
 
-	defaultProfile, err := ioutil.ReadFile("./presets/x264.xml")
+	// Variable frame rate profiles
+	vfrResolutions := []*video.Resolution{
+		{Width: 1280, Height: 720},
+		{Width: 1920, Height: 1080},
+		{Width: 2560, Height: 1440},
+		{Width: 3840, Height: 2160},
+	}
+	for _, resolution := range vfrResolutions {
+		for _, quality := range qualities {
+			profile := &avc.EncodeProfile{
+				Source:        fmt.Sprintf("vfr %dx%d %vfps peak %vfps crf%v", resolution.Width, resolution.Height, video.FPS30, video.FPS60, quality),
+				Width:         resolution.Width,
+				Height:        resolution.Height,
+				FrameRate:     video.FPS30,
+				PeakFrameRate: video.FPS60,
+				RateFactor:    quality,
+				ThreadCount:   16,
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Interlaced and telecined profiles
+	scanResolutions := []*video.Resolution{
+		{Width: 720, Height: 480, SampleAspect: video.NewRatio(8, 9), FrameRate: video.FPS29970, ScanType: video.Interlaced, FieldOrder: video.BottomFieldFirst},
+		{Width: 720, Height: 576, SampleAspect: video.NewRatio(16, 15), FrameRate: video.FPS25, ScanType: video.Interlaced, FieldOrder: video.TopFieldFirst},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: video.Interlaced, FieldOrder: video.TopFieldFirst},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS29970, ScanType: video.Interlaced, FieldOrder: video.TopFieldFirst},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: video.FakeInterlaced},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS29970, ScanType: video.FakeInterlaced},
+		{Width: 720, Height: 480, SampleAspect: video.NewRatio(8, 9), FrameRate: video.FPS23976, ScanType: video.Telecined},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS23976, ScanType: video.Telecined},
+	}
+	for _, resolution := range scanResolutions {
+		for _, quality := range qualities {
+			profile := &avc.EncodeProfile{
+				Source:       fmt.Sprintf("scan %dx%d%s %vfps crf%v", resolution.Width, resolution.Height, resolution.ScanType.Label(resolution.FieldOrder), resolution.FrameRate, quality),
+				Width:        resolution.Width,
+				Height:       resolution.Height,
+				SampleAspect: resolution.SampleAspect,
+				FrameRate:    resolution.FrameRate,
+				ScanType:     resolution.ScanType,
+				FieldOrder:   resolution.FieldOrder,
+				RateFactor:   quality,
+				ThreadCount:  16,
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Mezzanine profiles
+	mezzanineResolutions := []*video.Resolution{
+		{Width: 1920, Height: 1080},
+		{Width: 3840, Height: 2160},
+	}
+	mezzanineFramerates := []video.FrameRate{video.FPS23976, video.FPS25, video.FPS29970}
+	for _, resolution := range mezzanineResolutions {
+		for _, framerate := range mezzanineFramerates {
+			profile := &avc.EncodeProfile{
+				Source:       fmt.Sprintf("mezzanine %dx%d %vfps %s", resolution.Width, resolution.Height, framerate, video.FormatLabel(10, video.Chroma422)),
+				Width:        resolution.Width,
+				Height:       resolution.Height,
+				FrameRate:    framerate,
+				BitDepth:     10,
+				ChromaFormat: video.Chroma422,
+				RateFactor:   avc.UltraQuality,
+				ThreadCount:  16,
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Lossless profiles, using the same resolutions and frame rates as mezzanine profiles
+	gopModes := []encode.GOPMode{encode.IntraOnly, encode.ShortGOP}
+	for _, resolution := range mezzanineResolutions {
+		for _, framerate := range mezzanineFramerates {
+			for _, gopMode := range gopModes {
+				for _, chromaFormat := range []video.ChromaFormat{video.Chroma420, video.Chroma422} {
+					bitDepth := opx.Ternary(chromaFormat == video.Chroma420, uint8(8), uint8(10))
+					profile := &avc.EncodeProfile{
+						Source:       fmt.Sprintf("lossless %dx%d %vfps %s %db%s", resolution.Width, resolution.Height, framerate, gopMode.Label(), bitDepth, chromaFormat.Label()),
+						Width:        resolution.Width,
+						Height:       resolution.Height,
+						FrameRate:    framerate,
+						BitDepth:     bitDepth,
+						ChromaFormat: chromaFormat,
+						Lossless:     true,
+						GOPMode:      gopMode,
+						ThreadCount:  16,
+					}
+					profiles = append(profiles, profile)
+				}
+			}
+		}
+	}
+
+	// Edit profiles, using the same resolutions and frame rates as mezzanine profiles
+	editGOPModes := []encode.GOPMode{encode.EditGOP, encode.EditIntra}
+	for _, resolution := range mezzanineResolutions {
+		for _, framerate := range mezzanineFramerates {
+			for _, gopMode := range editGOPModes {
+				profile := &avc.EncodeProfile{
+					Source:      fmt.Sprintf("edit %dx%d %vfps %s", resolution.Width, resolution.Height, framerate, gopMode.Label()),
+					Width:       resolution.Width,
+					Height:      resolution.Height,
+					FrameRate:   framerate,
+					RateFactor:  avc.HighQuality,
+					GOPMode:     gopMode,
+					ThreadCount: 16,
+				}
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	// Delivery profiles, for distributors with fixed bitrate specs
+	deliveries := []struct {
+		resolution *video.Resolution
+		bitRate    uint32
+		targetSize uint32
+	}{
+		{&video.Resolution{Width: 1920, Height: 1080}, 8000, 4096},
+		{&video.Resolution{Width: 3840, Height: 2160}, 25000, 16384},
+	}
+	deliveryFramerates := []video.FrameRate{video.FPS23976, video.FPS25}
+	deliveryDuration := 2 * time.Hour
+	for _, delivery := range deliveries {
+		rateControls := []encode.RateControl{
+			{Mode: encode.CappedCRF, BitRate: delivery.bitRate},
+			{Mode: encode.ABR, BitRate: delivery.bitRate},
+			{Mode: encode.TwoPassABR, BitRate: delivery.bitRate},
+			{Mode: encode.CBR, BitRate: delivery.bitRate},
+			{Mode: encode.TargetSize, TargetSize: delivery.targetSize, Duration: deliveryDuration},
+		}
+		for _, framerate := range deliveryFramerates {
+			for _, rateControl := range rateControls {
+				profile := &avc.EncodeProfile{
+					Source:      fmt.Sprintf("delivery %dx%d %vfps %s", delivery.resolution.Width, delivery.resolution.Height, framerate, rateControl.Label()),
+					Width:       delivery.resolution.Width,
+					Height:      delivery.resolution.Height,
+					FrameRate:   framerate,
+					RateFactor:  avc.HighQuality,
+					RateControl: rateControl,
+					ThreadCount: 16,
+				}
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	// Proxy profiles, derived from generic high quality profiles of common master resolutions
+	for _, resolution := range mfResolutions {
+		for _, framerate := range mfFramerates {
+			master := &avc.EncodeProfile{
+				Width:       resolution.Width,
+				Height:      resolution.Height,
+				FrameRate:   framerate,
+				RateFactor:  avc.HighQuality,
+				ThreadCount: 16,
+			}
+			profiles = append(profiles, proxyProfiles(master)...)
+		}
+	}
+
+	// Profile matching a quality target replaces the profile matrix
+	if *target != "" {
+		profile, err := matchedProfile(*target, *size, frameRate)
+		if err != nil {
+			logger.Error(err, "invalid argument")
+			os.Exit(1)
+		}
+		profiles = []*avc.EncodeProfile{profile}
+	}
+
+	// Cap CRF profiles by VBV at the maximum of their level, unless opted out
+	if !*uncapped {
+		for _, profile := range profiles {
+			profile.RateControl = profile.RateControl.Capped()
+		}
+	}
+
+	// Let Hybrid adjust the colors of profiles to the input, if requested
+	if *colorFromInput {
+		for _, profile := range profiles {
+			profile.ColorFromInput = true
+		}
+	}
+
+	// Skip profiles that exceed the highest level
+	supportedProfiles := []*avc.EncodeProfile{}
+	for _, profile := range profiles {
+		if avc.MinLevel(profile.Width, profile.Height, profile.LevelFrameRate(), profile.ScanType) == 0 {
+			logger.Warnf("no AVC level supports profile %s", profile.Source)
+			continue
+		}
+		supportedProfiles = append(supportedProfiles, profile)
+	}
+	profiles = supportedProfiles
+
+	// Skip profiles whose estimated encoder memory exceeds the budget
+	allParams := []*EncodeParams{}
+	budgetProfiles := []*avc.EncodeProfile{}
+	for _, profile := range profiles {
+		params := createSetting(profile, targetHost)
+		if budget > 0 && uint64(params.EncoderMemoryMiB) > budget {
+			logger.Warnf("estimated encoder memory %d MiB of %s exceeds budget of %d MiB", params.EncoderMemoryMiB, params.Name, budget)
+			continue
+		}
+		allParams = append(allParams, params)
+		budgetProfiles = append(budgetProfiles, profile)
+	}
+	profiles = budgetProfiles
+
+	profileManifest := manifest.New("x264", *format)
+	if *manifestTime {
+		profileManifest.Stamp(time.Now())
+	}
+	switch *format {
+	case "hybrid":
+		template := loadTemplate()
+		for i, params := range allParams {
+			fileName, content := saveSetting(template, params)
+			if fileName != "" {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, templateFile, content, params)
+			}
+		}
+	case "shell", "json":
+		for i, params := range allParams {
+			fileName, content := saveCommandLine(*format, params)
+			if fileName != "" {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, "", content, params)
+			}
+		}
+	case "ffmpeg", "ffpreset":
+		for i, params := range allParams {
+			fileName, content := saveFFmpeg(*format, params)
+			if fileName != "" {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, "", content, params)
+			}
+		}
+	case "handbrake":
+		fileName, content := saveHandBrake(allParams)
+		if fileName != "" {
+			for i, params := range allParams {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, "", content, params)
+			}
+		}
+	default:
+		logger.Error(fmt.Errorf("unknown output format %q", *format), "invalid argument")
+		os.Exit(1)
+	}
+	err = profileManifest.Save("x264 manifest")
+	if err != nil {
+		logger.Error(err, "cannot write manifest")
+	}
+	logger.Info("x264 profiles generated successfully.")
+}
+
+// Read the Hybrid template and validate it against EncodeParams.
+// Exit immediately if the template is unusable.
+func loadTemplate() *template.Template {
+	defaultProfile, err := ioutil.ReadFile(templateFile)
 	if err != nil {
 		logger.Error(err, "failed to read template file")
+		os.Exit(1)
 	}
 	template, err := template.New("x264").Parse(string(defaultProfile))
 	if err != nil {
 		logger.Error(err, "failed to parse profile")
+		os.Exit(1)
 	}
-	for _, profile := range profiles {
-		params := createSetting(profile)
-		saveSetting(template, params)
+	unused, err := hybrid.Validate(template, EncodeParams{})
+	if err != nil {
+		logger.Error(err, "invalid template")
+		os.Exit(1)
 	}
-	logger.Info("x264 profiles generated successfully.")
+	for _, field := range unused {
+		logger.Warnf("parameter %s is not used by template %s", field, template.Name())
+	}
+	return template
 }
 
-// Create EncodeParams based on EncodeProfile.
-func createSetting(profile *avc.EncodeProfile) *EncodeParams {
-	quality := "L"
-	if float64(profile.RateFactor) <= float64(17) {
-		quality = "X"
-	} else if float64(profile.RateFactor) <= float64(22) {
-		quality = "H"
+// Return the profile reaching the quality target at the size and frame rate, named after the target,
+// whose rate factor is equivalent to the reference rate factor of the target.
+// Profiles of all codecs matching the same target, size and frame rate have the same name.
+func matchedProfile(targetName, size string, frameRate video.FrameRate) (*avc.EncodeProfile, error) {
+	target, err := encode.FindQualityTarget(targetName)
+	if err != nil {
+		return nil, err
 	}
+	width, height, err := video.ParseSize(size)
+	if err != nil {
+		return nil, err
+	}
+	rateFactor := avc.EquivalentRateFactor(target.Reference)
+	return &avc.EncodeProfile{
+		Name:        fmt.Sprintf("%dx%d@%s-%s", width, height, frameRate.Label(), target.Name),
+		Source:      fmt.Sprintf("match %s %dx%d %vfps crf%v", target.Name, width, height, frameRate, rateFactor),
+		Width:       width,
+		Height:      height,
+		FrameRate:   frameRate,
+		RateFactor:  rateFactor,
+		ThreadCount: 16,
+	}, nil
+}
+
+// Return names of all quality targets separated by comma.
+func qualityTargetNames() string {
+	names := []string{}
+	for _, target := range encode.QualityTargets {
+		names = append(names, target.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Return the proxy profiles of the master profile at all proxy sizes, named after the master profile.
+// Proxies are 8-bit 4:2:0 with preview quality and edit GOP, and keep the color description of the master.
+func proxyProfiles(master *avc.EncodeProfile) []*avc.EncodeProfile {
+	masterResolution := &video.Resolution{
+		Width:        master.Width,
+		Height:       master.Height,
+		SampleAspect: master.SampleAspect,
+		FrameRate:    master.FrameRate,
+		ScanType:     master.ScanType,
+		FieldOrder:   master.FieldOrder,
+	}
+	proxies := []*avc.EncodeProfile{}
+	for _, size := range []encode.ProxySize{encode.ProxyHalf, encode.ProxyQuarter} {
+		resolution := size.Resolution(masterResolution)
+		proxies = append(proxies, &avc.EncodeProfile{
+			Name:          profileName(master) + " " + size.Label(),
+			Source:        fmt.Sprintf("proxy %dx%d of %s", resolution.Width, resolution.Height, profileName(master)),
+			Width:         resolution.Width,
+			Height:        resolution.Height,
+			SampleAspect:  resolution.SampleAspect,
+			FrameRate:     master.FrameRate,
+			PeakFrameRate: master.PeakFrameRate,
+			ScanType:      master.ScanType,
+			FieldOrder:    master.FieldOrder,
+			RateFactor:    avc.PreviewQuality,
+			GOPMode:       encode.ProxyGOPMode,
+			Color:         master.ColorDescription(),
+			Proxy:         true,
+			ThreadCount:   master.ThreadCount,
+		})
+	}
+	return proxies
+}
+
+// Return the name of EncodeProfile, which is generated from its properties if not defined.
+func profileName(profile *avc.EncodeProfile) string {
+	if profile.Name != "" {
+		return profile.Name
+	}
+	quality := profile.RateFactor.Label()
+	if profile.Lossless {
+		quality = "LL"
+	} else if profile.RateControl.Mode.IsBitRate() {
+		quality = profile.RateControl.Label()
+	}
+	frameRate := profile.FrameRate.Label()
+	if profile.IsVariableFrameRate() {
+		frameRate += "vfr" + profile.PeakFrameRate.Label()
+	}
+	name := fmt.Sprintf("%dx%d%s@%s-%s", profile.Width, profile.Height, profile.ScanType.Label(profile.FieldOrder), frameRate, quality)
+	if formatLabel := video.FormatLabel(profile.BitDepth, profile.ChromaFormat); formatLabel != "" {
+		name += "-" + formatLabel
+	}
+	if gopLabel := profile.GOPMode.Label(); gopLabel != "" {
+		name += "-" + gopLabel
+	}
+	if !profile.Lossless && profile.RateControl.Mode == encode.CappedCRF && profile.RateControl.BitRate > 0 {
+		name += "-" + profile.RateControl.Label()
+	}
+	return name
+}
+
+// Create EncodeParams based on EncodeProfile.
+func createSetting(profile *avc.EncodeProfile, targetHost *host.Host) *EncodeParams {
 	params := &EncodeParams{
-		Name:        opx.Ternary(profile.Name != "", profile.Name, fmt.Sprintf("%dx%d@%4.2f-%s", profile.Width, profile.Height, profile.FrameRate, quality)),
-		Width:       profile.Width,
-		Height:      profile.Height,
-		FrameRate:   profile.FrameRate,
-		RateFactor:  float64(profile.RateFactor),
-		ThreadCount: profile.ThreadCount,
-	}
-	level := avc.MinLevel(profile.Width, profile.Height, profile.FrameRate)
+		Name:              profileName(profile),
+		Width:             profile.Width,
+		Height:            profile.Height,
+		FrameRate:         profile.FrameRate,
+		PeakFrameRate:     profile.LevelFrameRate(),
+		VariableFrameRate: profile.IsVariableFrameRate(),
+		RateFactor:        float64(profile.RateFactor),
+		ThreadCount:       profile.ThreadCount,
+	}
+	level := avc.MinLevel(profile.Width, profile.Height, profile.LevelFrameRate(), profile.ScanType)
+	if profile.Lossless {
+		// lossless bitrate usually exceeds the level chosen by frame size and rate
+		bitRate := encode.LosslessBitRate(profile.Width, profile.Height, profile.LevelFrameRate(), profile.OutputBitDepth(), profile.ChromaFormat)
+		if bitRateLevel := avc.MinLevelByBitRate(level, bitRate, profile.ProfileName()); bitRateLevel != 0 {
+			level = bitRateLevel
+		} else {
+			logger.Warnf("estimated lossless bitrate %d kbps of %s exceeds all AVC levels", bitRate, params.Name)
+		}
+	} else if bitRate := profile.RateControl.RequestedBitRate(); bitRate > 0 {
+		// requested bitrate may exceed the level chosen by frame size and rate
+		if bitRateLevel := avc.MinLevelByBitRate(level, uint64(bitRate), profile.ProfileName()); bitRateLevel != 0 {
+			level = bitRateLevel
+		} else {
+			logger.Warnf("requested bitrate %d kbps of %s exceeds all AVC levels", bitRate, params.Name)
+		}
+	}
 	x264Profile := avc.ProfileByLevel(level)
-	meRange, aqStrength := factorsByResolution(profile.Width)
-	refFrame, bFrame, aqStrengthModifier := factorsByRateFactor(profile.RateFactor, profile.FrameRate)
+	meRange, aqStrength := factorsByResolution(profile.Width, profile.Height)
+	refFrame, bFrame, aqStrengthModifier := factorsByRateFactor(profile.RateFactor, profile.FrameRate.Float64())
 
 	params.AVCLevel = float64(level) / 10
 	params.RefFrame = mathxt.MinUint8(x264Profile.RefFrameMax, refFrame)
 	params.MeRange = meRange
-	params.BFrame = bFrame
-	params.KeyInterval = uint16(math.Ceil(profile.FrameRate) * 10)
-	params.InputLookahead = mathxt.MaxUint8(params.ThreadCount*5, 30)
-	params.RCLookahead = uint16(math.Ceil(profile.FrameRate) * 2)
+	params.BFrame = mathxt.MinUint8(bFrame, profile.GOPMode.MaxBFrames())
+	params.KeyInterval = uint16(profile.GOPMode.KeyInterval(profile.FrameRate))
+	params.KeyIntervalMin = uint16(profile.GOPMode.MinKeyInterval(profile.FrameRate))
+	params.SceneCut = opx.Ternary(profile.GOPMode.IsEdit(), 0, uint8(40))
+	params.FastDecode = profile.GOPMode.IsEdit()
+	params.Lossless = profile.Lossless
+	params.NoPsy = profile.Lossless
+	applyRateControl(params, profile, x264Profile)
+	if targetHost != nil {
+		params.ThreadCount = avc.Threads(targetHost.LogicalCores, profile.Height, profile.ScanType)
+	}
+	params.InputLookahead = uint8(min(max(uint16(params.ThreadCount)*5, 30), 250))
+	params.RCLookahead = uint16(profile.FrameRate.Frames(2))
 	params.AQStrength = aqStrength + aqStrengthModifier
+	params.SampleAspect = opx.Ternary(profile.SampleAspect.IsZero(), video.SquarePixel, profile.SampleAspect)
+	params.DisplayAspect = video.DisplayAspect(profile.Width, profile.Height, profile.SampleAspect)
+	params.Interlaced = profile.ScanType == video.Interlaced
+	params.BottomFieldFirst = params.Interlaced && profile.FieldOrder == video.BottomFieldFirst
+	params.FakeInterlaced = profile.ScanType == video.FakeInterlaced
+	params.Pulldown = opx.Ternary(profile.ScanType == video.Telecined, "32", "off")
+	params.PicStruct = profile.ScanType != video.Progressive
+	params.AVCProfile = profile.ProfileName()
+	params.Transform8x8 = params.AVCProfile != "Main"
+	params.BitDepth = profile.OutputBitDepth()
+	params.OutputColorSpace = profile.ChromaFormat.ColorSpace()
+	params.PixelFormat = video.PixelFormat(params.BitDepth, profile.ChromaFormat)
+	params.AutoFormat = params.BitDepth == 8 && profile.ChromaFormat == video.Chroma420
+	params.AdjustVUIToInput = profile.ColorFromInput
+	color := profile.ColorDescription()
+	params.VUIColorPrimes = color.Primaries
+	params.VUITransfer = color.Transfer
+	params.VUIColorMatrix = color.Matrix
+	params.VUIRange = color.Range
+	params.VUIChromaLocation = color.ChromaLocation
+	// x264 chooses threads by the machine running it when ThreadCount is 0, which is assumed to be this one
+	memoryThreads := params.ThreadCount
+	if memoryThreads == 0 {
+		memoryThreads = avc.Threads(localHost().LogicalCores, profile.Height, profile.ScanType)
+	}
+	params.EncoderMemoryMiB, params.DecoderMemoryMiB = avc.EstimateMemory(profile.Width, profile.Height, params.BitDepth, profile.ChromaFormat,
+		params.RefFrame, params.BFrame, params.RCLookahead, params.InputLookahead, memoryThreads)
 	return params
 }
 
+// Apply the rate control of EncodeProfile to EncodeParams.
+// Bitrate and VBV not requested by the profile are the maximum of the level, lossless coding ignores rate control.
+func applyRateControl(params *EncodeParams, profile *avc.EncodeProfile, x264Profile *avc.AVCProfile) {
+	rateControl := profile.RateControl
+	if profile.Lossless {
+		rateControl = encode.RateControl{}
+	}
+	levelBitRate := x264Profile.MaxBitRate(profile.ProfileName())
+	params.EncodingType = hybrid.EncodingType(rateControl.Mode)
+	params.Passes = rateControl.Mode.Passes()
+	params.BitRate = rateControl.AverageBitRate(levelBitRate)
+	params.TargetSize = rateControl.TargetSize
+	params.PreferTargetSize = rateControl.Mode == encode.TargetSize
+	params.FastFirstPass = params.Passes > 1
+	params.VBVMaxBitRate, params.VBVBufferSize = rateControl.VBV(levelBitRate, x264Profile.MaxCPBSize(profile.ProfileName()))
+	if rateControl.Mode == encode.CappedCRF {
+		params.RateFactorMax = params.RateFactor + encode.CRFMaxOffset
+	}
+	params.NalHRD = opx.Ternary(rateControl.Mode == encode.CBR, "cbr", "none")
+}
+
 // Save the EnodeParms to disk.
-func saveSetting(template *template.Template, params *EncodeParams) {
+// Return the file name and content written, or empty file name on failure.
+func saveSetting(template *template.Template, params *EncodeParams) (string, []byte) {
 	fileName := fmt.Sprintf("x264 %s.xml", params.Name)
 
-	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	buffer := &bytes.Buffer{}
+	err := template.Execute(buffer, params)
 	if err != nil {
-		logger.Error(err, "cannot write to file", fileName)
-		return
+		logger.Error(err, fileName)
+		return "", nil
 	}
-	defer file.Close()
-
-	err = template.Execute(file, params)
+	err = os.WriteFile(fileName, buffer.Bytes(), 0644)
 	if err != nil {
-		logger.Error(err, fileName)
+		logger.Error(err, "cannot write to file", fileName)
+		return "", nil
 	}
+	return fileName, buffer.Bytes()
 }
 
-// Determine the motion estimation range and AQ strength based on the video width.
-func factorsByResolution(width uint16) (meRange uint8, aqStrength float64) {
+// Determine the motion estimation range and AQ strength based on the long edge of the video,
+// so portrait video is treated the same as landscape video of the same size.
+func factorsByResolution(width, height uint32) (meRange uint8, aqStrength float64) {
 	meRange = uint8(24)
 	aqStrength = float64(1)
 
-	if width >= (3840 * 15 / 16) {
+	longEdge := video.LongEdge(width, height)
+	if longEdge >= (7680 * 15 / 16) {
+		meRange = uint8(92)
+		aqStrength = float64(0.6)
+	} else if longEdge >= (3840 * 15 / 16) {
 		meRange = uint8(64)
 		aqStrength = float64(0.7)
-	} else if width >= (2560 * 15 / 16) {
+	} else if longEdge >= (2560 * 15 / 16) {
 		meRange = uint8(48)
 		aqStrength = float64(0.75)
-	} else if width >= (1920 * 7 / 8) {
+	} else if longEdge >= (1920 * 7 / 8) {
 		meRange = uint8(32)
 		aqStrength = float64(0.9)
-	} else if width >= (1280 * 7 / 8) {
+	} else if longEdge >= (1280 * 7 / 8) {
 		meRange = uint8(32)
 		aqStrength = float64(1)
 	} else {
@@ -189,21 +726,22 @@ func factorsByResolution(width uint16) (meRange uint8, aqStrength float64) {
 	return meRange, aqStrength
 }
 
-// Determine the reference frame count, B-frame count, and AQ strength modifier based on the rate factor and frame rate.
+// Determine the reference frame count, B-frame count, and AQ strength modifier based on the quality tier of the rate factor and frame rate.
 func factorsByRateFactor(quality avc.RateFactor, frameRate float64) (refFrame, bFrame uint8, aqStrengthModifier float64) {
 	refFrame = opx.Ternary(frameRate >= 32, uint8(5), uint8(3))
 	bFrame = uint8(7)
 	aqStrengthModifier = float64(0.15)
 
-	if float64(quality) <= float64(17) {
+	switch quality.TierIndex() {
+	case 0:
 		refFrame += 2
 		bFrame = uint8(16)
 		aqStrengthModifier = float64(0.05)
-	} else if float64(quality) <= float64(22) {
+	case 1:
 		refFrame += 1
 		bFrame = uint8(12)
 		aqStrengthModifier = float64(0.1)
-	} else {
+	default:
 		refFrame += 0
 		bFrame = uint8(7)
 		aqStrengthModifier = float64(0.15)
@@ -0,0 +1,582 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/avc"
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/host"
+	"github.com/lukaz17/hybrid-profile-generator-go/hybrid"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestTemplateMatchesEncodeParams(t *testing.T) {
+	content, err := os.ReadFile("../../presets/x264.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("x264").Parse(string(content)))
+	unused, err := hybrid.Validate(tmpl, EncodeParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	if !reflect.DeepEqual(unused, want) {
+		t.Errorf("unused parameters = %v, want %v", unused, want)
+	}
+}
+
+func TestCreateSettingFrameRate(t *testing.T) {
+	tests := []struct {
+		frameRate       video.FrameRate
+		wantName        string
+		wantKeyInterval uint16
+		wantRCLookahead uint16
+	}{
+		{video.FPS23976, "1920x1080@23.976-H", 240, 48},
+		{video.FPS25, "1920x1080@25.00-H", 250, 50},
+		{video.FPS29970, "1920x1080@29.97-H", 300, 60},
+		{video.FPS59940, "1920x1080@59.94-H", 600, 120},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: tt.frameRate, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.KeyInterval != tt.wantKeyInterval || params.RCLookahead != tt.wantRCLookahead {
+				t.Errorf("KeyInterval, RCLookahead = %d, %d, want %d, %d", params.KeyInterval, params.RCLookahead, tt.wantKeyInterval, tt.wantRCLookahead)
+			}
+		})
+	}
+}
+
+func TestCreateSettingVariableFrameRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		frameRate     video.FrameRate
+		peakFrameRate video.FrameRate
+		wantName      string
+		wantVariable  bool
+		wantLevel     uint8
+	}{
+		{"constant", video.FPS30, video.FrameRate{}, "1920x1080@30.00-H", false, avc.MinLevel(1920, 1080, video.FPS30, video.Progressive)},
+		{"nominal 30 peak 60", video.FPS30, video.FPS60, "1920x1080@30.00vfr60.00-H", true, avc.MinLevel(1920, 1080, video.FPS60, video.Progressive)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: tt.frameRate, PeakFrameRate: tt.peakFrameRate, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.VariableFrameRate != tt.wantVariable {
+				t.Errorf("VariableFrameRate = %v, want %v", params.VariableFrameRate, tt.wantVariable)
+			}
+			if want := float64(tt.wantLevel) / 10; params.AVCLevel < want {
+				t.Errorf("AVCLevel = %v, want at least %v", params.AVCLevel, want)
+			}
+			// GOP length follows the nominal frame rate
+			if params.KeyInterval != 300 {
+				t.Errorf("KeyInterval = %d, want 300", params.KeyInterval)
+			}
+		})
+	}
+}
+
+func TestCreateSettingScanType(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolution video.Resolution
+		wantName   string
+		wantParams [5]interface{}
+		wantArgs   []string
+	}{
+		{"progressive", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS25}, "1920x1080@25.00-H", [5]interface{}{false, false, false, "off", false}, nil},
+		{"interlaced tff", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: video.Interlaced}, "1920x1080i@25.00-H", [5]interface{}{true, false, false, "off", true}, []string{"--tff", "--pic-struct"}},
+		{"interlaced bff", video.Resolution{Width: 720, Height: 480, FrameRate: video.FPS29970, ScanType: video.Interlaced, FieldOrder: video.BottomFieldFirst}, "720x480ib@29.97-H", [5]interface{}{true, true, false, "off", true}, []string{"--bff", "--pic-struct"}},
+		{"fake interlaced", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: video.FakeInterlaced, FieldOrder: video.BottomFieldFirst}, "1920x1080psf@25.00-H", [5]interface{}{false, false, true, "off", true}, []string{"--fake-interlaced", "--pic-struct"}},
+		{"telecined", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS23976, ScanType: video.Telecined}, "1920x1080tc@23.976-H", [5]interface{}{false, false, false, "32", true}, []string{"--pulldown", "32", "--pic-struct"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{
+				Width:       tt.resolution.Width,
+				Height:      tt.resolution.Height,
+				FrameRate:   tt.resolution.FrameRate,
+				ScanType:    tt.resolution.ScanType,
+				FieldOrder:  tt.resolution.FieldOrder,
+				RateFactor:  avc.HighQuality,
+				ThreadCount: 16,
+			}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			got := [5]interface{}{params.Interlaced, params.BottomFieldFirst, params.FakeInterlaced, params.Pulldown, params.PicStruct}
+			if got != tt.wantParams {
+				t.Errorf("Interlaced, BottomFieldFirst, FakeInterlaced, Pulldown, PicStruct = %v, want %v", got, tt.wantParams)
+			}
+			// scan flags are appended after the common options
+			args := createCommandLine(params).Args()
+			commonArgs := createCommandLine(createSetting(&avc.EncodeProfile{Width: tt.resolution.Width, Height: tt.resolution.Height, FrameRate: tt.resolution.FrameRate, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)).Args()
+			if tail := args[len(commonArgs):]; !slices.Equal(tail, tt.wantArgs) {
+				t.Errorf("scan arguments = %q, want %q", tail, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFactorsByResolution(t *testing.T) {
+	tests := []struct {
+		name           string
+		width          uint32
+		height         uint32
+		wantMeRange    uint8
+		wantAQStrength float64
+	}{
+		{"360p", 640, 360, 24, 1.1},
+		{"720p", 1280, 720, 32, 1},
+		{"1080p", 1920, 1080, 32, 0.9},
+		{"1080p portrait", 1080, 1920, 32, 0.9},
+		{"1440p", 2560, 1440, 48, 0.75},
+		{"2160p", 3840, 2160, 64, 0.7},
+		{"2160p portrait", 2160, 3840, 64, 0.7},
+		{"4320p", 7680, 4320, 92, 0.6},
+		{"4320p portrait", 4320, 7680, 92, 0.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meRange, aqStrength := factorsByResolution(tt.width, tt.height)
+			if meRange != tt.wantMeRange || aqStrength != tt.wantAQStrength {
+				t.Errorf("factorsByResolution(%d, %d) = %d, %v, want %d, %v", tt.width, tt.height, meRange, aqStrength, tt.wantMeRange, tt.wantAQStrength)
+			}
+		})
+	}
+}
+
+func TestCreateSettingColor(t *testing.T) {
+	full := video.ColorDescription{Primaries: "bt709", Transfer: "bt709", Matrix: "bt709", Range: "full"}
+	tests := []struct {
+		name     string
+		width    uint32
+		height   uint32
+		color    video.ColorDescription
+		want     video.ColorDescription
+		wantArgs []string
+	}{
+		{"NTSC DVD", 720, 480, video.ColorDescription{}, video.BT601NTSC, []string{"--colorprim", "smpte170m", "--transfer", "smpte170m", "--colormatrix", "smpte170m", "--range", "tv", "--chromaloc", "0"}},
+		{"PAL DVD", 720, 576, video.ColorDescription{}, video.BT601PAL, []string{"--colorprim", "bt470bg", "--transfer", "bt470bg", "--colormatrix", "bt470bg", "--range", "tv", "--chromaloc", "0"}},
+		{"qHD", 960, 540, video.ColorDescription{}, video.BT709, []string{"--colorprim", "bt709", "--transfer", "bt709", "--colormatrix", "bt709", "--range", "tv", "--chromaloc", "0"}},
+		{"PAL widescreen square pixels", 1024, 576, video.ColorDescription{}, video.BT709, []string{"--colorprim", "bt709", "--transfer", "bt709", "--colormatrix", "bt709", "--range", "tv", "--chromaloc", "0"}},
+		{"1080p", 1920, 1080, video.ColorDescription{}, video.BT709, []string{"--colorprim", "bt709", "--transfer", "bt709", "--colormatrix", "bt709", "--range", "tv", "--chromaloc", "0"}},
+		{"full range", 1920, 1080, full, full, []string{"--colorprim", "bt709", "--transfer", "bt709", "--colormatrix", "bt709", "--range", "pc", "--chromaloc", "0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: tt.width, Height: tt.height, FrameRate: video.FPS25, Color: tt.color, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+			got := video.ColorDescription{Primaries: params.VUIColorPrimes, Transfer: params.VUITransfer, Matrix: params.VUIColorMatrix, Range: params.VUIRange, ChromaLocation: params.VUIChromaLocation}
+			if got != tt.want {
+				t.Errorf("color = %+v, want %+v", got, tt.want)
+			}
+			args := createCommandLine(params).Args()
+			i := slices.Index(args, "--colorprim")
+			if i < 0 || i+len(tt.wantArgs) > len(args) || !slices.Equal(args[i:i+len(tt.wantArgs)], tt.wantArgs) {
+				t.Errorf("Args() = %q, want %q", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCreateSettingFormat(t *testing.T) {
+	tests := []struct {
+		name            string
+		bitDepth        uint8
+		chromaFormat    video.ChromaFormat
+		wantName        string
+		wantProfile     string
+		wantPixelFormat string
+		wantArgs        []string
+	}{
+		{"8-bit 4:2:0", 8, video.Chroma420, "1920x1080@25.00-X", "High", "yuv420p", []string{"--profile", "high", "--level"}},
+		{"10-bit 4:2:0", 10, video.Chroma420, "1920x1080@25.00-X-10b", "High10", "yuv420p10le", []string{"--profile", "high10", "--output-depth", "10", "--level"}},
+		{"10-bit 4:2:2", 10, video.Chroma422, "1920x1080@25.00-X-10b422", "High422", "yuv422p10le", []string{"--profile", "high422", "--output-depth", "10", "--output-csp", "i422", "--level"}},
+		{"8-bit 4:4:4", 8, video.Chroma444, "1920x1080@25.00-X-8b444", "High444", "yuv444p", []string{"--profile", "high444", "--output-csp", "i444", "--level"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, BitDepth: tt.bitDepth, ChromaFormat: tt.chromaFormat, RateFactor: avc.UltraQuality, ThreadCount: 16}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.AVCProfile != tt.wantProfile || params.PixelFormat != tt.wantPixelFormat {
+				t.Errorf("AVCProfile/PixelFormat = %s/%s, want %s/%s", params.AVCProfile, params.PixelFormat, tt.wantProfile, tt.wantPixelFormat)
+			}
+			if autoFormat := tt.bitDepth == 8 && tt.chromaFormat == video.Chroma420; params.AutoFormat != autoFormat {
+				t.Errorf("AutoFormat = %v, want %v", params.AutoFormat, autoFormat)
+			}
+			if args := createCommandLine(params).Args(); !slices.Equal(args[:len(tt.wantArgs)], tt.wantArgs) {
+				t.Errorf("Args() = %q, want prefix %q", args, tt.wantArgs)
+			}
+			// FFmpeg selects the pixel format only when it is not the default
+			ffmpegArgs := createFFmpeg(params).Args()
+			if i := slices.Index(ffmpegArgs, "-pix_fmt"); params.AutoFormat != (i < 0) || (i >= 0 && ffmpegArgs[i+1] != tt.wantPixelFormat) {
+				t.Errorf("FFmpeg Args() = %q, want pix_fmt %q unless AutoFormat", ffmpegArgs, tt.wantPixelFormat)
+			}
+		})
+	}
+}
+
+func TestCreateSettingLossless(t *testing.T) {
+	tests := []struct {
+		name            string
+		gopMode         encode.GOPMode
+		wantName        string
+		wantBFrame      uint8
+		wantKeyInterval uint16
+	}{
+		{"intra-only", encode.IntraOnly, "1920x1080@25.00-LL-intra", 0, 1},
+		{"short GOP", encode.ShortGOP, "1920x1080@25.00-LL-sgop", 16, 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, Lossless: true, GOPMode: tt.gopMode, ThreadCount: 16}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if !params.Lossless || !params.NoPsy || params.AVCProfile != "High444" {
+				t.Errorf("Lossless/NoPsy/AVCProfile = %v/%v/%s, want true/true/High444", params.Lossless, params.NoPsy, params.AVCProfile)
+			}
+			// 311040 kbps of lossless 1080p25 needs level 5 with High 4:4:4 Predictive
+			if params.AVCLevel != 5.0 {
+				t.Errorf("AVCLevel = %v, want 5.0", params.AVCLevel)
+			}
+			if params.BFrame != tt.wantBFrame || params.KeyInterval != tt.wantKeyInterval {
+				t.Errorf("BFrame/KeyInterval = %d/%d, want %d/%d", params.BFrame, params.KeyInterval, tt.wantBFrame, tt.wantKeyInterval)
+			}
+			args := createCommandLine(params).Args()
+			if i := slices.Index(args, "--qp"); i < 0 || args[i+1] != "0" || slices.Contains(args, "--crf") || !slices.Contains(args, "--no-psy") {
+				t.Errorf("Args() = %q, want --qp 0 and --no-psy without --crf", args)
+			}
+		})
+	}
+}
+
+func TestCreateSettingGOPMode(t *testing.T) {
+	tests := []struct {
+		gopMode         encode.GOPMode
+		wantName        string
+		wantBFrame      uint8
+		wantKeyInterval uint16
+		wantGOPArgs     []string
+	}{
+		{encode.LongGOP, "1920x1080@25.00-H", 12, 250, []string{"--keyint", "250", "--rc-lookahead"}},
+		{encode.EditGOP, "1920x1080@25.00-H-edit", 2, 25, []string{"--keyint", "25", "--min-keyint", "25", "--no-scenecut", "--rc-lookahead"}},
+		{encode.EditIntra, "1920x1080@25.00-H-editintra", 0, 1, []string{"--keyint", "1", "--min-keyint", "1", "--no-scenecut", "--rc-lookahead"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, GOPMode: tt.gopMode, ThreadCount: 16}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.BFrame != tt.wantBFrame || params.KeyInterval != tt.wantKeyInterval {
+				t.Errorf("BFrame/KeyInterval = %d/%d, want %d/%d", params.BFrame, params.KeyInterval, tt.wantBFrame, tt.wantKeyInterval)
+			}
+			if params.FastDecode != tt.gopMode.IsEdit() {
+				t.Errorf("FastDecode = %v, want %v", params.FastDecode, tt.gopMode.IsEdit())
+			}
+			args := createCommandLine(params).Args()
+			i := slices.Index(args, "--keyint")
+			if i < 0 || i+len(tt.wantGOPArgs) > len(args) || !slices.Equal(args[i:i+len(tt.wantGOPArgs)], tt.wantGOPArgs) {
+				t.Errorf("Args() = %q, want %q", args, tt.wantGOPArgs)
+			}
+			// edit modes replace deblocking and weighted prediction with options friendly to fast decoding
+			fastDecodeArgs := []string{"--weightp", "0", "--no-weightb", "--no-deblock", "--no-cabac"}
+			j := slices.Index(args, fastDecodeArgs[0])
+			hasFastDecode := j >= 0 && j+len(fastDecodeArgs) <= len(args) && slices.Equal(args[j:j+len(fastDecodeArgs)], fastDecodeArgs)
+			if hasFastDecode != tt.gopMode.IsEdit() || slices.Contains(args, "--deblock") == tt.gopMode.IsEdit() {
+				t.Errorf("Args() = %q, want fast decode options %v", args, tt.gopMode.IsEdit())
+			}
+		})
+	}
+}
+
+func TestProxyProfiles(t *testing.T) {
+	master := &avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, BitDepth: 10, ChromaFormat: video.Chroma422, RateFactor: avc.HighQuality, ThreadCount: 16}
+	tests := []struct {
+		wantName   string
+		wantWidth  uint32
+		wantHeight uint32
+	}{
+		{"1920x1080@25.00-H-10b422 proxy-half", 960, 540},
+		{"1920x1080@25.00-H-10b422 proxy-quarter", 480, 270},
+	}
+	proxies := proxyProfiles(master)
+	if len(proxies) != len(tests) {
+		t.Fatalf("len(proxyProfiles()) = %d, want %d", len(proxies), len(tests))
+	}
+	for i, tt := range tests {
+		proxy := proxies[i]
+		if proxy.Width != tt.wantWidth || proxy.Height != tt.wantHeight {
+			t.Errorf("proxy %d = %dx%d, want %dx%d", i, proxy.Width, proxy.Height, tt.wantWidth, tt.wantHeight)
+		}
+		if proxy.RateFactor != avc.PreviewQuality || proxy.GOPMode != encode.ProxyGOPMode {
+			t.Errorf("proxy %d RateFactor/GOPMode = %v/%d, want %v/%d", i, proxy.RateFactor, proxy.GOPMode, avc.PreviewQuality, encode.ProxyGOPMode)
+		}
+		// proxies keep the colors of the master, even when their size defaults to SD colors
+		if proxy.ColorDescription() != video.BT709 {
+			t.Errorf("proxy %d ColorDescription() = %+v, want BT.709", i, proxy.ColorDescription())
+		}
+		params := createSetting(proxy, nil)
+		if params.Name != tt.wantName {
+			t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+		}
+		if params.AVCProfile != "Main" || params.BitDepth != 8 || !params.AutoFormat {
+			t.Errorf("AVCProfile/BitDepth/AutoFormat = %s/%d/%v, want Main/8/true", params.AVCProfile, params.BitDepth, params.AutoFormat)
+		}
+		// Main profile has no 8x8 transform
+		if params.Transform8x8 || !slices.Equal(createCommandLine(params).Args()[:2], []string{"--profile", "main"}) {
+			t.Errorf("Transform8x8 = %v, want false with --profile main", params.Transform8x8)
+		}
+	}
+}
+
+func TestCreateSettingRateControl(t *testing.T) {
+	tests := []struct {
+		name             string
+		rateControl      encode.RateControl
+		wantName         string
+		wantLevel        float64
+		wantEncodingType string
+		wantPasses       uint8
+		wantRateArgs     []string
+	}{
+		{"crf", encode.RateControl{}, "1920x1080@25.00-H", 4.0, "constant rate factor (1-pass)", 1, []string{"--crf", "20"}},
+		{"capped crf", encode.RateControl{Mode: encode.CappedCRF}, "1920x1080@25.00-H", 4.0, "constant rate factor (1-pass)", 1, []string{"--crf", "20", "--crf-max", "25", "--vbv-maxrate", "25000", "--vbv-bufsize", "31250"}},
+		{"capped crf at bitrate", encode.RateControl{Mode: encode.CappedCRF, BitRate: 8000}, "1920x1080@25.00-H-ccrf8000", 4.0, "constant rate factor (1-pass)", 1, []string{"--crf", "20", "--crf-max", "25", "--vbv-maxrate", "8000", "--vbv-bufsize", "10000"}},
+		{"abr", encode.RateControl{Mode: encode.ABR, BitRate: 8000}, "1920x1080@25.00-abr8000", 4.0, "average bitrate (1-pass)", 1, []string{"--bitrate", "8000"}},
+		{"2 pass", encode.RateControl{Mode: encode.TwoPassABR, BitRate: 8000}, "1920x1080@25.00-2pass8000", 4.0, "average bitrate (2-pass)", 2, []string{"--bitrate", "8000"}},
+		{"cbr", encode.RateControl{Mode: encode.CBR, BitRate: 8000}, "1920x1080@25.00-cbr8000", 4.0, "average bitrate (1-pass)", 1, []string{"--bitrate", "8000", "--vbv-maxrate", "8000", "--vbv-bufsize", "10000", "--nal-hrd", "cbr"}},
+		{"target size", encode.RateControl{Mode: encode.TargetSize, TargetSize: 4096, Duration: 2 * time.Hour}, "1920x1080@25.00-size4096-120min", 4.0, "average bitrate (2-pass)", 2, []string{"--bitrate", "4772"}},
+		{"bitrate raises level", encode.RateControl{Mode: encode.ABR, BitRate: 60000}, "1920x1080@25.00-abr60000", 4.1, "average bitrate (1-pass)", 1, []string{"--bitrate", "60000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, RateControl: tt.rateControl, ThreadCount: 16}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.AVCLevel != tt.wantLevel {
+				t.Errorf("AVCLevel = %v, want %v", params.AVCLevel, tt.wantLevel)
+			}
+			if params.EncodingType != tt.wantEncodingType || params.Passes != tt.wantPasses {
+				t.Errorf("EncodingType/Passes = %q/%d, want %q/%d", params.EncodingType, params.Passes, tt.wantEncodingType, tt.wantPasses)
+			}
+			if params.PreferTargetSize != (tt.rateControl.Mode == encode.TargetSize) {
+				t.Errorf("PreferTargetSize = %v, want %v", params.PreferTargetSize, tt.rateControl.Mode == encode.TargetSize)
+			}
+			if params.FastFirstPass != (tt.wantPasses > 1) {
+				t.Errorf("FastFirstPass = %v, want %v", params.FastFirstPass, tt.wantPasses > 1)
+			}
+			// rate control options follow the level, and precede the reference frames
+			args := createCommandLine(params).Args()
+			i, j := slices.Index(args, "--level")+2, slices.Index(args, "--ref")
+			if i < 2 || j < i || !slices.Equal(args[i:j], tt.wantRateArgs) {
+				t.Errorf("Args() = %q, want rate control arguments %q", args, tt.wantRateArgs)
+			}
+		})
+	}
+}
+
+func TestShellComment(t *testing.T) {
+	tests := []struct {
+		rateControl encode.RateControl
+		want        string
+	}{
+		{encode.RateControl{}, "x264 1920x1080@25.00-H"},
+		{encode.RateControl{Mode: encode.TwoPassABR, BitRate: 8000}, "x264 1920x1080@25.00-2pass8000, run 2 times with --pass 1 to --pass 2"},
+	}
+	for _, tt := range tests {
+		params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, RateControl: tt.rateControl, ThreadCount: 16}, nil)
+		if got := shellComment(params); got != tt.want {
+			t.Errorf("shellComment() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCreateSettingQualityTier(t *testing.T) {
+	tests := []struct {
+		rateFactor avc.RateFactor
+		wantName   string
+		wantBFrame uint8
+	}{
+		{avc.UltraQuality, "1920x1080@25.00-X", 16},
+		{17, "1920x1080@25.00-X17", 16},
+		{17.5, "1920x1080@25.00-H17.5", 12},
+		{avc.HighQuality, "1920x1080@25.00-H", 12},
+		{avc.NormalQuality, "1920x1080@25.00-L", 7},
+		{30, "1920x1080@25.00-L30", 7},
+	}
+	for _, tt := range tests {
+		// the tier names the profile and selects the heuristics, so they never disagree
+		params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: tt.rateFactor, ThreadCount: 16}, nil)
+		if params.Name != tt.wantName || params.BFrame != tt.wantBFrame {
+			t.Errorf("CRF %v: Name/BFrame = %q/%d, want %q/%d", tt.rateFactor, params.Name, params.BFrame, tt.wantName, tt.wantBFrame)
+		}
+	}
+}
+
+func TestMatchedProfile(t *testing.T) {
+	tests := []struct {
+		target         string
+		size           string
+		wantName       string
+		wantRateFactor avc.RateFactor
+		wantErr        bool
+	}{
+		{"high", "3840x2160", "3840x2160@23.976-high", 20, false},
+		{"transparent", "1920x1080", "1920x1080@23.976-transparent", avc.UltraQuality, false},
+		{"archive-small", "1920x1080", "1920x1080@23.976-archive-small", avc.PreviewQuality, false},
+		{"best", "1920x1080", "", 0, true},
+		{"high", "1920", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.size, func(t *testing.T) {
+			profile, err := matchedProfile(tt.target, tt.size, video.FPS23976)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchedProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// the name is shared by all codecs, the rate factor is equivalent to the x264 reference
+			if profile.Name != tt.wantName || profile.RateFactor != tt.wantRateFactor {
+				t.Errorf("Name/RateFactor = %q/%v, want %q/%v", profile.Name, profile.RateFactor, tt.wantName, tt.wantRateFactor)
+			}
+			if params := createSetting(profile, nil); params.Name != tt.wantName {
+				t.Errorf("createSetting().Name = %q, want %q", params.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCreateSettingHost(t *testing.T) {
+	tests := []struct {
+		name               string
+		scanType           video.ScanType
+		host               *host.Host
+		wantThreads        uint8
+		wantInputLookahead uint8
+	}{
+		{"no host", video.Progressive, nil, 16, 80},
+		{"laptop", video.Progressive, &host.Host{LogicalCores: 8, NUMANodes: 1}, 12, 60},
+		{"server limited by macroblock rows", video.Progressive, &host.Host{LogicalCores: 64, NUMANodes: 2}, 34, 170},
+		{"server limited by field macroblock rows", video.Interlaced, &host.Host{LogicalCores: 64, NUMANodes: 2}, 17, 85},
+		{"single core", video.Progressive, &host.Host{LogicalCores: 1, NUMANodes: 1}, 1, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: tt.scanType, RateFactor: avc.HighQuality, ThreadCount: 16}, tt.host)
+			if params.ThreadCount != tt.wantThreads || params.InputLookahead != tt.wantInputLookahead {
+				t.Errorf("ThreadCount/InputLookahead = %d/%d, want %d/%d", params.ThreadCount, params.InputLookahead, tt.wantThreads, tt.wantInputLookahead)
+			}
+		})
+	}
+}
+
+func TestCreateSettingMemory(t *testing.T) {
+	hd := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+	uhd := createSetting(&avc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS25, BitDepth: 10, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+	if hd.EncoderMemoryMiB == 0 || hd.DecoderMemoryMiB == 0 || hd.DecoderMemoryMiB >= hd.EncoderMemoryMiB {
+		t.Errorf("1080p EncoderMemoryMiB/DecoderMemoryMiB = %d/%d, want positive encoder above decoder", hd.EncoderMemoryMiB, hd.DecoderMemoryMiB)
+	}
+	if uhd.EncoderMemoryMiB <= hd.EncoderMemoryMiB || uhd.DecoderMemoryMiB <= hd.DecoderMemoryMiB {
+		t.Errorf("2160p 10-bit EncoderMemoryMiB/DecoderMemoryMiB = %d/%d, want more than 1080p %d/%d",
+			uhd.EncoderMemoryMiB, uhd.DecoderMemoryMiB, hd.EncoderMemoryMiB, hd.DecoderMemoryMiB)
+	}
+}
+
+func TestTemplateFastFirstPass(t *testing.T) {
+	content, err := os.ReadFile("../../presets/x264.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("x264").Parse(string(content)))
+	tests := []struct {
+		rateControl encode.RateControl
+		want        string
+	}{
+		{encode.RateControl{}, `name="fast1stPass" value="false"`},
+		{encode.RateControl{Mode: encode.ABR, BitRate: 8000}, `name="fast1stPass" value="false"`},
+		{encode.RateControl{Mode: encode.TwoPassABR, BitRate: 8000}, `name="fast1stPass" value="true"`},
+	}
+	for _, tt := range tests {
+		params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality, RateControl: tt.rateControl, ThreadCount: 16}, nil)
+		buffer := &bytes.Buffer{}
+		if err := tmpl.Execute(buffer, params); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buffer.String(), tt.want) {
+			t.Errorf("%s: template output does not contain %s", params.Name, tt.want)
+		}
+	}
+}
+
+func TestTemplateColorFromInput(t *testing.T) {
+	content, err := os.ReadFile("../../presets/x264.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("x264").Parse(string(content)))
+	tests := []struct {
+		colorFromInput bool
+		want           string
+	}{
+		{false, "false"},
+		{true, "true"},
+	}
+	for _, tt := range tests {
+		params := createSetting(&avc.EncodeProfile{Width: 720, Height: 576, FrameRate: video.FPS25, ColorFromInput: tt.colorFromInput, RateFactor: avc.HighQuality, ThreadCount: 16}, nil)
+		buffer := &bytes.Buffer{}
+		if err := tmpl.Execute(buffer, params); err != nil {
+			t.Fatal(err)
+		}
+		// the generated colors are still written, as the fallback when the input has none
+		for _, want := range []string{
+			`name="adjustVUIColorMatrixToInput" value="` + tt.want + `"`,
+			`name="adjustVUIColorPrimesToInput" value="` + tt.want + `"`,
+			`name="adjustVUIColorRangeToInput" value="` + tt.want + `"`,
+			`name="adjustVUIColorTransferToInput" value="` + tt.want + `"`,
+			`name="vuiColorPrimesValue" value="bt470bg"`,
+		} {
+			if !strings.Contains(buffer.String(), want) {
+				t.Errorf("ColorFromInput %v: template output does not contain %s", tt.colorFromInput, want)
+			}
+		}
+	}
+}
+
+func TestCreateSettingMemoryAutomaticThreads(t *testing.T) {
+	// threads 0 lets x264 choose by the local machine, so memory is estimated for the threads it chooses
+	params := createSetting(&avc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: avc.HighQuality}, nil)
+	if params.ThreadCount != 0 {
+		t.Errorf("ThreadCount = %d, want 0", params.ThreadCount)
+	}
+	threads := avc.Threads(host.Detect().LogicalCores, 1080, video.Progressive)
+	want, _ := avc.EstimateMemory(1920, 1080, params.BitDepth, video.Chroma420, params.RefFrame, params.BFrame, params.RCLookahead, params.InputLookahead, threads)
+	if params.EncoderMemoryMiB != want {
+		t.Errorf("EncoderMemoryMiB = %d, want %d for %d threads", params.EncoderMemoryMiB, want, threads)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/cmdline"
+	"github.com/tforce-io/tf-golib/opx"
+)
+
+// Create native x265 arguments equivalent to the Hybrid template for the EncodeParams.
+func createCommandLine(params *EncodeParams) *cmdline.Arguments {
+	args := cmdline.New("x265")
+	if params.AllowNonConformance {
+		args.Flag("allow-non-conformance")
+	} else {
+		args.Add("level-idc", fmt.Sprintf("%2.1f", params.HEVCLevel))
+	}
+	if params.HEVCTier == "High" {
+		args.Flag("high-tier")
+	} else {
+		args.Flag("no-high-tier")
+	}
+	if params.HEVCProfile != "Main" {
+		args.Add("profile", strings.ToLower(params.HEVCProfile))
+	}
+	if params.BitDepth > 8 {
+		args.Add("output-depth", fmt.Sprint(params.BitDepth))
+	}
+	if params.OutputColorSpace != "i420" {
+		args.Add("input-csp", params.OutputColorSpace)
+	}
+	if params.Lossless {
+		args.Flag("lossless")
+	} else if params.BitRate > 0 {
+		args.Add("bitrate", fmt.Sprint(params.BitRate))
+	} else {
+		args.Add("crf", fmt.Sprintf("%2.1f", params.RateFactor))
+		if params.RateFactorMax > 0 {
+			args.Add("crf-max", fmt.Sprintf("%2.1f", params.RateFactorMax))
+		}
+	}
+	if params.VBVMaxBitRate > 0 {
+		args.Add("vbv-maxrate", fmt.Sprint(params.VBVMaxBitRate)).
+			Add("vbv-bufsize", fmt.Sprint(params.VBVBufferSize))
+	}
+	if params.StrictCBR {
+		args.Flag("strict-cbr").
+			Flag("hrd")
+	}
+	if params.FastFirstPass {
+		args.Flag("no-slow-firstpass")
+	}
+	args.Add("ref", fmt.Sprint(params.RefFrame)).
+		Add("bframes", fmt.Sprint(params.BFrame)).
+		Add("b-adapt", "2").
+		Add("me", "star").
+		Add("merange", fmt.Sprint(params.MeRange)).
+		Add("subme", "4").
+		Add("rd", "6").
+		Add("psy-rd", fmt.Sprint(params.PsyRD)).
+		Add("psy-rdoq", fmt.Sprint(params.PsyRDOQ))
+	if params.FastDecode {
+		args.Flag("no-deblock").
+			Flag("no-weightp").
+			Flag("no-weightb")
+	} else {
+		args.Add("deblock", "-4:-3")
+	}
+	args.Flag("no-sao").
+		Flag("no-open-gop").
+		Add("keyint", fmt.Sprint(params.KeyInterval))
+	if params.KeyIntervalMin > 0 {
+		args.Add("min-keyint", fmt.Sprint(params.KeyIntervalMin))
+	}
+	if params.SceneCut == 0 {
+		args.Flag("no-scenecut")
+	}
+	args.Add("rc-lookahead", fmt.Sprint(params.RCLookahead)).
+		Add("aq-mode", fmt.Sprint(params.AQMode)).
+		Add("aq-strength", fmt.Sprintf("%2.1f", params.AQStrength)).
+		Add("sar", params.SampleAspect.String()).
+		Add("colorprim", params.VUIColorPrimes).
+		Add("transfer", params.VUITransfer).
+		Add("colormatrix", params.VUIColorMatrix).
+		Add("range", params.VUIRange).
+		Add("chromaloc", fmt.Sprint(params.VUIChromaLocation))
+	if params.VariableFrameRate {
+		// VUI timing would declare the nominal frame rate as constant, timecodes are applied by the muxer
+		args.Flag("no-vui-timing-info")
+	}
+	if params.SignalHDR {
+		args.Flag("hdr10")
+	}
+	if params.HDROpt {
+		args.Flag("hdr10-opt")
+	}
+	if params.SignalHLG {
+		args.Add("atc-sei", "18")
+	}
+	if params.MasterDisplay != "" {
+		args.Add("master-display", params.MasterDisplay).
+			Add("max-cll", fmt.Sprintf("%d,%d", params.MaxCLL, params.MaxFALL))
+	}
+	if params.DynamicMetadataFile != "" {
+		args.Add("dhdr10-info", params.DynamicMetadataFile)
+	}
+	if params.DolbyVisionProfile != "none" {
+		args.Add("dolby-vision-profile", params.DolbyVisionProfile)
+		if params.DolbyVisionRpuFile != "" {
+			args.Add("dolby-vision-rpu", params.DolbyVisionRpuFile)
+		}
+	}
+	args.Add("pools", params.Pools)
+	if params.FrameThreads > 0 {
+		args.Add("frame-threads", fmt.Sprint(params.FrameThreads))
+	}
+	if params.LookaheadThreads > 0 {
+		args.Add("lookahead-threads", fmt.Sprint(params.LookaheadThreads))
+	}
+	if !params.WavefrontPP {
+		args.Flag("no-wpp")
+	}
+	if params.Interlaced {
+		args.Add("interlace", params.FieldOrder)
+	}
+	return args
+}
+
+// Return the comment of shell snippet, which tells to run multi-pass profiles once per pass.
+func shellComment(params *EncodeParams) string {
+	if params.Passes > 1 {
+		return fmt.Sprintf("x265 %s, run %d times with --pass 1 to --pass %d", params.Name, params.Passes, params.Passes)
+	}
+	return "x265 " + params.Name
+}
+
+// Save the native arguments of EncodeParams to disk as shell snippet or JSON array.
+// Return the file name and content written, or empty file name on failure.
+func saveCommandLine(format string, params *EncodeParams) (string, []byte) {
+	args := createCommandLine(params)
+	fileName := fmt.Sprintf("x265 %s.sh", params.Name)
+	content := []byte(args.Shell(shellComment(params)))
+	if format == "json" {
+		fileName = fmt.Sprintf("x265 %s.json", params.Name)
+		json, err := args.JSON()
+		if err != nil {
+			logger.Error(err, fileName)
+			return "", nil
+		}
+		content = json
+	}
+
+	err := os.WriteFile(fileName, content, opx.Ternary(format == "json", os.FileMode(0644), os.FileMode(0755)))
+	if err != nil {
+		logger.Error(err, "cannot write to file", fileName)
+		return "", nil
+	}
+	return fileName, content
+}
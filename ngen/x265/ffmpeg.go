@@ -0,0 +1,60 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/ffmpeg"
+)
+
+// Create FFmpeg libx265 options equivalent to the Hybrid template for the EncodeParams.
+// Options without FFmpeg counterpart are passed through -x265-params.
+func createFFmpeg(params *EncodeParams) *ffmpeg.Arguments {
+	args := ffmpeg.New("libx265", "x265-params")
+	if !params.AutoFormat {
+		args.Set("profile:v", strings.ToLower(params.HEVCProfile)).
+			Set("pix_fmt", params.PixelFormat)
+	}
+	if params.BitRate > 0 {
+		args.Set("b:v", fmt.Sprintf("%dk", params.BitRate))
+	} else if !params.Lossless {
+		args.Set("crf", fmt.Sprintf("%2.1f", params.RateFactor))
+	}
+	if params.VBVMaxBitRate > 0 {
+		args.Set("maxrate", fmt.Sprintf("%dk", params.VBVMaxBitRate)).
+			Set("bufsize", fmt.Sprintf("%dk", params.VBVBufferSize))
+	}
+	args.Set("color_primaries", params.VUIColorPrimes).
+		Set("color_trc", params.VUITransfer).
+		Set("colorspace", params.VUIColorMatrix).
+		Set("color_range", ffmpeg.ColorRange(params.VUIRange)).
+		Set("chroma_sample_location", ffmpeg.ChromaLocation(params.VUIChromaLocation))
+	args.Param(createCommandLine(params).Without("profile", "output-depth", "input-csp", "crf", "bitrate", "vbv-maxrate", "vbv-bufsize", "colorprim", "transfer", "colormatrix", "range", "chromaloc")...)
+	return args
+}
+
+// Save the FFmpeg options of EncodeParams to disk as command fragment or ffpreset file.
+// Return the file name and content written, or empty file name on failure.
+func saveFFmpeg(format string, params *EncodeParams) (string, []byte) {
+	args := createFFmpeg(params)
+	fileName := fmt.Sprintf("x265 %s.txt", params.Name)
+	content := []byte(args.Fragment() + "\n")
+	if format == "ffpreset" {
+		fileName = fmt.Sprintf("libx265-%s.ffpreset", params.Name)
+		content = []byte(args.Preset("x265 " + params.Name))
+	}
+
+	err := os.WriteFile(fileName, content, 0644)
+	if err != nil {
+		logger.Error(err, "cannot write to file", fileName)
+		return "", nil
+	}
+	return fileName, content
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/cmdline"
+	"github.com/lukaz17/hybrid-profile-generator-go/handbrake"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+	"github.com/tforce-io/tf-golib/opx"
+)
+
+// Create HandBrake preset equivalent to the Hybrid template for the EncodeParams.
+// Options without HandBrake counterpart are passed as advanced x265 options.
+// Lossless coding has quality slider 0, and the turbo first pass follows FastFirstPass.
+func createHandBrake(params *EncodeParams) *handbrake.Preset {
+	args := createCommandLine(params)
+	return &handbrake.Preset{
+		PresetName:            "x265 " + params.Name,
+		PresetDescription:     fmt.Sprintf("x265 %dx%d at %s fps, %s, level %2.1f", params.Width, params.Height, params.FrameRate.Label(), rateDescription(params), params.HEVCLevel),
+		Type:                  handbrake.CustomPreset,
+		PictureWidth:          params.Width,
+		PictureHeight:         params.Height,
+		PictureKeepRatio:      true,
+		PictureUseMaximumSize: true,
+		PicturePAR:            opx.Ternary(params.SampleAspect == video.SquarePixel, "off", "custom"),
+		PicturePARWidth:       params.SampleAspect.Num,
+		PicturePARHeight:      params.SampleAspect.Den,
+		VideoEncoder:          opx.Ternary(params.BitDepth > 8, fmt.Sprintf("x265_%dbit", params.BitDepth), "x265"),
+		VideoPreset:           "medium",
+		VideoProfile:          strings.ToLower(params.HEVCProfile),
+		VideoLevel:            opx.Ternary(params.AllowNonConformance, "auto", fmt.Sprintf("%2.1f", params.HEVCLevel)),
+		VideoOptionExtra:      cmdline.JoinParams(args.Without("profile", "output-depth", "input-csp", "level-idc", "crf", "bitrate", "sar", "colorprim", "transfer", "colormatrix", "range", "chromaloc")),
+		VideoQualityType:      opx.Ternary(params.BitRate > 0, handbrake.AverageBitRate, handbrake.ConstantQuality),
+		VideoQualitySlider:    opx.Ternary(params.Lossless, float64(0), params.RateFactor),
+		VideoAvgBitrate:       params.BitRate,
+		VideoFramerate:        params.PeakFrameRate.String(),
+		VideoFramerateMode:    opx.Ternary(params.VariableFrameRate, "pfr", "cfr"),
+		VideoMultiPass:        params.Passes > 1,
+		VideoTurboMultiPass:   params.FastFirstPass,
+	}
+}
+
+// Return the description of rate control, e.g. "CRF 20" or "2-pass 8000 kbps".
+func rateDescription(params *EncodeParams) string {
+	if params.BitRate > 0 {
+		return fmt.Sprintf("%d-pass %d kbps", params.Passes, params.BitRate)
+	}
+	return fmt.Sprintf("CRF %v", params.RateFactor)
+}
+
+// Save HandBrake presets of all EncodeParams to disk as a single importable file.
+// Return the file name and content written, or empty file name on failure.
+func saveHandBrake(allParams []*EncodeParams) (string, []byte) {
+	fileName := "x265 HandBrake.json"
+	presetFile := handbrake.NewPresetFile("x265")
+	for _, params := range allParams {
+		presetFile.Add(createHandBrake(params))
+	}
+
+	content, err := presetFile.JSON()
+	if err != nil {
+		logger.Error(err, fileName)
+		return "", nil
+	}
+	err = os.WriteFile(fileName, content, 0644)
+	if err != nil {
+		logger.Error(err, "cannot write to file", fileName)
+		return "", nil
+	}
+	return fileName, content
+}
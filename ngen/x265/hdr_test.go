@@ -0,0 +1,214 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/hevc"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+// hdrParams holds the EncodeParams fields set by applyDynamicRange and the color description.
+type hdrParams struct {
+	AQMode              uint8
+	HEVCProfile         string
+	BitDepth            uint8
+	VUIColorPrimes      string
+	VUITransfer         string
+	VUIColorMatrix      string
+	VUIChromaLocation   uint8
+	SignalHDR           bool
+	SignalHLG           bool
+	HDROpt              bool
+	MasterDisplay       string
+	MaxCLL              uint16
+	MaxFALL             uint16
+	DynamicMetadataFile string
+	DolbyVisionProfile  string
+	DolbyVisionRpuFile  string
+}
+
+func TestApplyDynamicRange(t *testing.T) {
+	p3 := hevc.DisplayP3D65.String()
+	tests := []struct {
+		dynamicRange hevc.DynamicRange
+		want         hdrParams
+		wantArgs     [][]string
+	}{
+		{
+			hevc.SDR,
+			hdrParams{AQMode: 4, HEVCProfile: "Main", BitDepth: 8, VUIColorPrimes: "bt709", VUITransfer: "bt709", VUIColorMatrix: "bt709", VUIChromaLocation: 0, DolbyVisionProfile: "none"},
+			nil,
+		},
+		{
+			hevc.HDR10,
+			hdrParams{AQMode: 3, HEVCProfile: "Main10", BitDepth: 10, VUIColorPrimes: "bt2020", VUITransfer: "smpte2084", VUIColorMatrix: "bt2020nc", VUIChromaLocation: 2, SignalHDR: true, HDROpt: true, MasterDisplay: p3, MaxCLL: 1000, MaxFALL: 400, DolbyVisionProfile: "none"},
+			[][]string{{"--profile", "main10", "--output-depth", "10"}, {"--colorprim", "bt2020", "--transfer", "smpte2084"}, {"--hdr10", "--hdr10-opt", "--master-display", p3, "--max-cll", "1000,400"}},
+		},
+		{
+			hevc.HDR10Plus,
+			hdrParams{AQMode: 3, HEVCProfile: "Main10", BitDepth: 10, VUIColorPrimes: "bt2020", VUITransfer: "smpte2084", VUIColorMatrix: "bt2020nc", VUIChromaLocation: 2, SignalHDR: true, HDROpt: true, MasterDisplay: p3, MaxCLL: 1000, MaxFALL: 400, DynamicMetadataFile: "title.json", DolbyVisionProfile: "none"},
+			[][]string{{"--hdr10"}, {"--dhdr10-info", "title.json"}},
+		},
+		{
+			hevc.HLG,
+			hdrParams{AQMode: 4, HEVCProfile: "Main10", BitDepth: 10, VUIColorPrimes: "bt2020", VUITransfer: "arib-std-b67", VUIColorMatrix: "bt2020nc", VUIChromaLocation: 2, SignalHLG: true, DolbyVisionProfile: "none"},
+			[][]string{{"--transfer", "arib-std-b67"}, {"--atc-sei", "18"}},
+		},
+		{
+			hevc.DolbyVision81,
+			hdrParams{AQMode: 3, HEVCProfile: "Main10", BitDepth: 10, VUIColorPrimes: "bt2020", VUITransfer: "smpte2084", VUIColorMatrix: "bt2020nc", VUIChromaLocation: 2, SignalHDR: true, HDROpt: true, MasterDisplay: p3, MaxCLL: 1000, MaxFALL: 400, DolbyVisionProfile: "8.1", DolbyVisionRpuFile: "title.json"},
+			[][]string{{"--dolby-vision-profile", "8.1", "--dolby-vision-rpu", "title.json"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dynamicRange.Label(), func(t *testing.T) {
+			profile := &hevc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS23976, DynamicRange: tt.dynamicRange, MetadataFile: "title.json", RateFactor: hevc.HighQuality}
+			if tt.dynamicRange.IsPQ() {
+				profile.MasteringDisplay = hevc.DisplayP3D65
+				profile.ContentLight = hevc.ContentLightLevel{MaxCLL: 1000, MaxFALL: 400}
+			}
+			params := createSetting(profile, nil)
+			got := hdrParams{
+				AQMode:              params.AQMode,
+				HEVCProfile:         params.HEVCProfile,
+				BitDepth:            params.BitDepth,
+				VUIColorPrimes:      params.VUIColorPrimes,
+				VUITransfer:         params.VUITransfer,
+				VUIColorMatrix:      params.VUIColorMatrix,
+				VUIChromaLocation:   params.VUIChromaLocation,
+				SignalHDR:           params.SignalHDR,
+				SignalHLG:           params.SignalHLG,
+				HDROpt:              params.HDROpt,
+				MasterDisplay:       params.MasterDisplay,
+				MaxCLL:              params.MaxCLL,
+				MaxFALL:             params.MaxFALL,
+				DynamicMetadataFile: params.DynamicMetadataFile,
+				DolbyVisionProfile:  params.DolbyVisionProfile,
+				DolbyVisionRpuFile:  params.DolbyVisionRpuFile,
+			}
+			if got != tt.want {
+				t.Errorf("createSetting() = %+v, want %+v", got, tt.want)
+			}
+
+			args := createCommandLine(params).Args()
+			for _, want := range tt.wantArgs {
+				if !containsArgs(args, want) {
+					t.Errorf("Args() = %q, want %q", args, want)
+				}
+			}
+			if !tt.dynamicRange.IsHDR() && (slices.Contains(args, "--profile") || slices.Contains(args, "--hdr10")) {
+				t.Errorf("Args() = %q, want no HDR arguments", args)
+			}
+		})
+	}
+}
+
+func TestApplyDynamicRangeColorFromInput(t *testing.T) {
+	tests := []struct {
+		dynamicRange   hevc.DynamicRange
+		colorFromInput bool
+		want           bool
+	}{
+		{hevc.SDR, false, false},
+		{hevc.SDR, true, true},
+		{hevc.HDR10, true, false},
+		{hevc.HLG, true, false},
+		{hevc.DolbyVision81, true, false},
+	}
+	for _, tt := range tests {
+		// HDR formats require BT.2020 colors, so only SDR follows the input
+		params := &EncodeParams{}
+		applyDynamicRange(params, &hevc.EncodeProfile{DynamicRange: tt.dynamicRange, ColorFromInput: tt.colorFromInput})
+		if params.AdjustVUIToInput != tt.want {
+			t.Errorf("DynamicRange %q with ColorFromInput %v: AdjustVUIToInput = %v, want %v", tt.dynamicRange.Label(), tt.colorFromInput, params.AdjustVUIToInput, tt.want)
+		}
+	}
+}
+
+func TestTemplateDynamicRange(t *testing.T) {
+	content, err := os.ReadFile("../../presets/x265.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("x265").Parse(string(content)))
+	tests := []struct {
+		dynamicRange hevc.DynamicRange
+		want         []string
+	}{
+		{hevc.SDR, []string{
+			`name="hevcProfile" value="Main"`,
+			`name="internalBitDepth" value="8-bit"`,
+			`name="signalHDR" value="false"`,
+			`name="signalMasterDisplayInfo" value="false"`,
+			`name="signalLightLevelInfo" value="false"`,
+			`name="adjustVUIColorPrimesToInput" value="false"`,
+			`name="masterDisplayInfo"/>`,
+			`name="vuiColorPrimesValue" value="bt709"`,
+			`name="adaptiveQuantizationMode" value="auto + edge"`,
+		}},
+		{hevc.HDR10, []string{
+			`name="hevcProfile" value="Main10"`,
+			`name="internalBitDepth" value="10-bit"`,
+			`name="signalHDR" value="true"`,
+			`name="signalMasterDisplayInfo" value="true"`,
+			`name="signalLightLevelInfo" value="true"`,
+			`name="adjustVUIColorMatrixToInput" value="false"`,
+			`name="adjustVUIColorTransferToInput" value="false"`,
+			`name="masterDisplayInfo" value="` + hevc.DisplayP3D65.String() + `"`,
+			`name="maxcll" value="1000"`,
+			`name="maxfall" value="400"`,
+			`name="vuiColorPrimesValue" value="bt2020"`,
+			`name="vuiTransferValue" value="smpte2084"`,
+			`name="hdrOpt" value="true"`,
+			`name="adaptiveQuantizationMode" value="auto + bias to dark scenes"`,
+		}},
+		{hevc.HLG, []string{
+			`name="signalHLG" value="true"`,
+			`name="signalHDR" value="false"`,
+			`name="vuiTransferValue" value="arib-std-b67"`,
+		}},
+		{hevc.DolbyVision81, []string{
+			`name="dolbyVisionProfile" value="8.1"`,
+			`name="dolbyVisionRpuFile" value="title.bin"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dynamicRange.Label(), func(t *testing.T) {
+			profile := &hevc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS23976, DynamicRange: tt.dynamicRange, MetadataFile: "title.bin", RateFactor: hevc.HighQuality}
+			if tt.dynamicRange.IsPQ() {
+				profile.MasteringDisplay = hevc.DisplayP3D65
+				profile.ContentLight = hevc.ContentLightLevel{MaxCLL: 1000, MaxFALL: 400}
+			}
+			buffer := &bytes.Buffer{}
+			if err := tmpl.Execute(buffer, createSetting(profile, nil)); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buffer.String(), want) {
+					t.Errorf("template output does not contain %s", want)
+				}
+			}
+		})
+	}
+}
+
+// Return true if args contains want as consecutive arguments.
+func containsArgs(args, want []string) bool {
+	for i := 0; i+len(want) <= len(args); i++ {
+		if slices.Equal(args[i:i+len(want)], want) {
+			return true
+		}
+	}
+	return false
+}
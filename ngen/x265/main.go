@@ -7,13 +7,20 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"os"
+	"strings"
 	"text/template"
+	"time"
 
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
 	"github.com/lukaz17/hybrid-profile-generator-go/hevc"
+	"github.com/lukaz17/hybrid-profile-generator-go/host"
+	"github.com/lukaz17/hybrid-profile-generator-go/hybrid"
+	"github.com/lukaz17/hybrid-profile-generator-go/manifest"
 	"github.com/lukaz17/hybrid-profile-generator-go/video"
 	"github.com/tforce-io/tf-golib/diag"
 	"github.com/tforce-io/tf-golib/opx"
@@ -22,27 +29,122 @@ import (
 
 var logger = diag.DefaultLogger{}
 
+// Path of the Hybrid template, relative to working directory.
+const templateFile = "./presets/x265.xml"
+
 // EncodeParams holds the parameters for encoding profiles.
+// The Hybrid template has no timecode option for variable frame rate, Hybrid applies input timecodes when muxing,
+// and the command line omits VUI timing info instead.
 type EncodeParams struct {
-	Name          string
-	Width         uint16
-	Height        uint16
-	FrameRate     float64
-	ThreadCount   uint8
-	RateFactor    float64
-	RateFactorMax float64
-	HEVCLevel     float64
-	HEVCTier      string
-	RefFrame      uint8
-	MeRange       uint8
-	BFrame        uint8
-	KeyInterval   uint16
-	RCLookahead   uint16
-	AQStrength    float64
+	Name                string          `template:"-"`
+	Width               uint32          `template:"-"`
+	Height              uint32          `template:"-"`
+	FrameRate           video.FrameRate `template:"-"`
+	PeakFrameRate       video.FrameRate `template:"-"`
+	SampleAspect        video.Ratio
+	DisplayAspect       video.Ratio `template:"-"`
+	VariableFrameRate   bool        `template:"-"`
+	Interlaced          bool
+	FieldOrder          string `template:"-"`
+	PicStruct           string
+	ThreadCount         uint16
+	FrameThreads        uint8
+	LookaheadThreads    uint8
+	Pools               string
+	WavefrontPP         bool
+	RateFactor          float64
+	RateFactorMax       float64
+	HEVCLevel           float64
+	HEVCTier            string
+	AllowNonConformance bool
+	Lossless            bool
+	PsyRD               float64
+	PsyRDOQ             float64
+	EncodingType        string
+	Passes              uint8 `template:"-"`
+	BitRate             uint32
+	TargetSize          uint32
+	PreferTargetSize    bool
+	FastFirstPass       bool
+	VBVMaxBitRate       uint32
+	VBVBufferSize       uint32
+	StrictCBR           bool
+	RefFrame            uint8
+	MeRange             uint8
+	BFrame              uint8
+	KeyInterval         uint16
+	KeyIntervalMin      uint16
+	SceneCut            uint8
+	FastDecode          bool
+	RCLookahead         uint16
+	AQStrength          float64
+	AQMode              uint8
+	HEVCProfile         string
+	BitDepth            uint8
+	OutputColorSpace    string
+	PixelFormat         string `template:"-"`
+	AutoFormat          bool
+	AdjustVUIToInput    bool
+	VUIColorPrimes      string
+	VUITransfer         string
+	VUIColorMatrix      string
+	VUIRange            string
+	VUIChromaLocation   uint8
+	SignalHDR           bool
+	SignalHLG           bool
+	HDROpt              bool
+	MasterDisplay       string
+	MaxCLL              uint16
+	MaxFALL             uint16
+	DynamicMetadataFile string
+	DolbyVisionProfile  string
+	DolbyVisionRpuFile  string
+	EncoderMemoryMiB    uint32 `template:"-"`
+	DecoderMemoryMiB    uint32 `template:"-"`
 }
 
 func main() {
-	profiles := []*hevc.EncodeProfile{}
+	format := flag.String("format", "hybrid", "output format: hybrid, shell, json, ffmpeg, ffpreset or handbrake")
+	uncapped := flag.Bool("uncapped", false, "do not cap CRF profiles by VBV and crf-max")
+	crf := flag.String("crf", fmt.Sprintf("%v,%v", hevc.NormalQuality, hevc.HighQuality), "comma separated rate factors of generic profiles")
+	tiers := flag.String("tiers", "", "quality tiers in form name:nominal:max, e.g. X:17:19,H:21:24,L:25:51")
+	target := flag.String("target", "", "generate only the profile matching a quality target: "+qualityTargetNames())
+	size := flag.String("size", "1920x1080", "size of the profile matching -target")
+	memoryBudget := flag.Uint64("memory-budget", 0, "skip profiles whose estimated encoder memory exceeds this budget in MiB, 0 for the memory of -host or no budget")
+	colorFromInput := flag.Bool("color-from-input", false, "let Hybrid adjust the color description of SDR profiles to the input")
+	hostFile := flag.String("host", "", "derive threads from the encoding host: auto to detect, or path of a JSON host file")
+	manifestTime := flag.Bool("manifest-time", false, "record the generation time in the manifest, which then differs between runs")
+	frameRate := video.FPS25
+	flag.TextVar(&frameRate, "fps", video.FPS25, "frame rate of the profile matching -target, e.g. 25 or 24000/1001")
+	flag.Parse()
+
+	rateFactors, err := encode.ParseRateFactors(*crf)
+	if err != nil {
+		logger.Error(err, "invalid argument")
+		os.Exit(1)
+	}
+	if *tiers != "" {
+		hevc.Tiers, err = encode.ParseQualityLadder(*tiers)
+		if err != nil {
+			logger.Error(err, "invalid argument")
+			os.Exit(1)
+		}
+	}
+	var targetHost *host.Host
+	if *hostFile != "" {
+		targetHost, err = host.Resolve(*hostFile)
+		if err != nil {
+			logger.Error(err, "invalid argument")
+			os.Exit(1)
+		}
+		logger.Infof("threads are derived from host with %d logical cores on %d NUMA nodes", targetHost.LogicalCores, targetHost.NUMANodes)
+	}
+	budget := host.MemoryBudget(*memoryBudget, targetHost)
+
+	profiles := []*hevc.EncodeProfile{
+		{Name: "NTSC HDV", Width: 1440, Height: 1080, SampleAspect: video.NewRatio(4, 3), FrameRate: video.FPS29970, ScanType: video.Interlaced, RateFactor: hevc.UltraQuality, Source: "named"},
+		{Name: "PAL HDV", Width: 1440, Height: 1080, SampleAspect: video.NewRatio(4, 3), FrameRate: video.FPS25, ScanType: video.Interlaced, RateFactor: hevc.UltraQuality, Source: "named"},
+	}
 	// Generic profiles
 	resolutions := []*video.Resolution{
 		{Width: 960, Height: 720},
@@ -53,16 +155,24 @@ func main() {
 		{Width: 1920, Height: 1440},
 		{Width: 2560, Height: 1440},
 		{Width: 3840, Height: 2160},
+		{Width: 7680, Height: 4320},
+		{Width: 720, Height: 1280},
+		{Width: 1080, Height: 1920},
+		{Width: 2160, Height: 3840},
 	}
-	framerates := []float64{25, 30, 50, 60}
-	qualities := []hevc.RateFactor{
-		hevc.NormalQuality,
-		hevc.HighQuality,
+	croppedAspects := []video.Ratio{video.Aspect239, video.Aspect235, video.Aspect200, video.Aspect185}
+	resolutions = append(resolutions, video.ResolutionsByAspect(1920, croppedAspects, video.Mod8)...)
+	resolutions = append(resolutions, video.ResolutionsByAspect(3840, croppedAspects, video.Mod8)...)
+	framerates := []video.FrameRate{video.FPS23976, video.FPS25, video.FPS29970, video.FPS30, video.FPS50, video.FPS59940, video.FPS60, video.FPS100, video.FPS120}
+	qualities := []hevc.RateFactor{}
+	for _, rateFactor := range rateFactors {
+		qualities = append(qualities, hevc.RateFactor(rateFactor))
 	}
 	for _, resolution := range resolutions {
 		for _, framerate := range framerates {
 			for _, quality := range qualities {
 				profile := &hevc.EncodeProfile{
+					Source:     fmt.Sprintf("generic %dx%d %vfps crf%v", resolution.Width, resolution.Height, framerate, quality),
 					Width:      resolution.Width,
 					Height:     resolution.Height,
 					FrameRate:  framerate,
@@ -78,10 +188,11 @@ func main() {
 		{Width: 2560, Height: 1440},
 		{Width: 3840, Height: 2160},
 	}
-	mfFramerates := []float64{25, 30}
+	mfFramerates := []video.FrameRate{video.FPS23976, video.FPS25, video.FPS30}
 	for _, resolution := range mfResolutions {
 		for _, framerate := range mfFramerates {
 			profile := &hevc.EncodeProfile{
+				Source:     fmt.Sprintf("master %dx%d %vfps", resolution.Width, resolution.Height, framerate),
 				Width:      resolution.Width,
 				Height:     resolution.Height,
 				FrameRate:  framerate,
@@ -91,126 +202,643 @@ func main() {
 		}
 	}
 
-	defaultProfile, err := ioutil.ReadFile("./presets/x265.xml")
+	// Variable frame rate profiles
+	vfrResolutions := []*video.Resolution{
+		{Width: 1280, Height: 720},
+		{Width: 1920, Height: 1080},
+		{Width: 2560, Height: 1440},
+		{Width: 3840, Height: 2160},
+	}
+	for _, resolution := range vfrResolutions {
+		for _, quality := range qualities {
+			profile := &hevc.EncodeProfile{
+				Source:        fmt.Sprintf("vfr %dx%d %vfps peak %vfps crf%v", resolution.Width, resolution.Height, video.FPS30, video.FPS60, quality),
+				Width:         resolution.Width,
+				Height:        resolution.Height,
+				FrameRate:     video.FPS30,
+				PeakFrameRate: video.FPS60,
+				RateFactor:    quality,
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Interlaced profiles
+	scanResolutions := []*video.Resolution{
+		{Width: 720, Height: 480, SampleAspect: video.NewRatio(8, 9), FrameRate: video.FPS29970, ScanType: video.Interlaced, FieldOrder: video.BottomFieldFirst},
+		{Width: 720, Height: 576, SampleAspect: video.NewRatio(16, 15), FrameRate: video.FPS25, ScanType: video.Interlaced, FieldOrder: video.TopFieldFirst},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: video.Interlaced, FieldOrder: video.TopFieldFirst},
+		{Width: 1920, Height: 1080, FrameRate: video.FPS29970, ScanType: video.Interlaced, FieldOrder: video.TopFieldFirst},
+	}
+	for _, resolution := range scanResolutions {
+		for _, quality := range qualities {
+			profile := &hevc.EncodeProfile{
+				Source:       fmt.Sprintf("scan %dx%d%s %vfps crf%v", resolution.Width, resolution.Height, resolution.ScanType.Label(resolution.FieldOrder), resolution.FrameRate, quality),
+				Width:        resolution.Width,
+				Height:       resolution.Height,
+				SampleAspect: resolution.SampleAspect,
+				FrameRate:    resolution.FrameRate,
+				ScanType:     resolution.ScanType,
+				FieldOrder:   resolution.FieldOrder,
+				RateFactor:   quality,
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Mezzanine profiles
+	mezzanineResolutions := []*video.Resolution{
+		{Width: 1920, Height: 1080},
+		{Width: 3840, Height: 2160},
+	}
+	mezzanineFramerates := []video.FrameRate{video.FPS23976, video.FPS25, video.FPS29970}
+	for _, resolution := range mezzanineResolutions {
+		for _, framerate := range mezzanineFramerates {
+			profile := &hevc.EncodeProfile{
+				Source:       fmt.Sprintf("mezzanine %dx%d %vfps %s", resolution.Width, resolution.Height, framerate, video.FormatLabel(10, video.Chroma422)),
+				Width:        resolution.Width,
+				Height:       resolution.Height,
+				FrameRate:    framerate,
+				BitDepth:     10,
+				ChromaFormat: video.Chroma422,
+				RateFactor:   hevc.UltraQuality,
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Lossless profiles, using the same resolutions and frame rates as mezzanine profiles
+	gopModes := []encode.GOPMode{encode.IntraOnly, encode.ShortGOP}
+	for _, resolution := range mezzanineResolutions {
+		for _, framerate := range mezzanineFramerates {
+			for _, gopMode := range gopModes {
+				for _, chromaFormat := range []video.ChromaFormat{video.Chroma420, video.Chroma422} {
+					bitDepth := opx.Ternary(chromaFormat == video.Chroma420, uint8(8), uint8(10))
+					profile := &hevc.EncodeProfile{
+						Source:       fmt.Sprintf("lossless %dx%d %vfps %s %db%s", resolution.Width, resolution.Height, framerate, gopMode.Label(), bitDepth, chromaFormat.Label()),
+						Width:        resolution.Width,
+						Height:       resolution.Height,
+						FrameRate:    framerate,
+						BitDepth:     bitDepth,
+						ChromaFormat: chromaFormat,
+						Lossless:     true,
+						GOPMode:      gopMode,
+					}
+					profiles = append(profiles, profile)
+				}
+			}
+		}
+	}
+
+	// Edit profiles, using the same resolutions and frame rates as mezzanine profiles
+	editGOPModes := []encode.GOPMode{encode.EditGOP, encode.EditIntra}
+	for _, resolution := range mezzanineResolutions {
+		for _, framerate := range mezzanineFramerates {
+			for _, gopMode := range editGOPModes {
+				profile := &hevc.EncodeProfile{
+					Source:     fmt.Sprintf("edit %dx%d %vfps %s", resolution.Width, resolution.Height, framerate, gopMode.Label()),
+					Width:      resolution.Width,
+					Height:     resolution.Height,
+					FrameRate:  framerate,
+					RateFactor: hevc.HighQuality,
+					GOPMode:    gopMode,
+				}
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	// Delivery profiles, for distributors with fixed bitrate specs
+	deliveries := []struct {
+		resolution *video.Resolution
+		bitRate    uint32
+		targetSize uint32
+	}{
+		{&video.Resolution{Width: 1920, Height: 1080}, 5000, 3072},
+		{&video.Resolution{Width: 3840, Height: 2160}, 16000, 12288},
+	}
+	deliveryFramerates := []video.FrameRate{video.FPS23976, video.FPS25}
+	deliveryDuration := 2 * time.Hour
+	for _, delivery := range deliveries {
+		rateControls := []encode.RateControl{
+			{Mode: encode.CappedCRF, BitRate: delivery.bitRate},
+			{Mode: encode.ABR, BitRate: delivery.bitRate},
+			{Mode: encode.TwoPassABR, BitRate: delivery.bitRate},
+			{Mode: encode.CBR, BitRate: delivery.bitRate},
+			{Mode: encode.TargetSize, TargetSize: delivery.targetSize, Duration: deliveryDuration},
+		}
+		for _, framerate := range deliveryFramerates {
+			for _, rateControl := range rateControls {
+				profile := &hevc.EncodeProfile{
+					Source:      fmt.Sprintf("delivery %dx%d %vfps %s", delivery.resolution.Width, delivery.resolution.Height, framerate, rateControl.Label()),
+					Width:       delivery.resolution.Width,
+					Height:      delivery.resolution.Height,
+					FrameRate:   framerate,
+					RateFactor:  hevc.HighQuality,
+					RateControl: rateControl,
+				}
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+
+	// Proxy profiles, derived from generic high quality profiles of common master resolutions
+	for _, resolution := range mfResolutions {
+		for _, framerate := range mfFramerates {
+			master := &hevc.EncodeProfile{
+				Width:      resolution.Width,
+				Height:     resolution.Height,
+				FrameRate:  framerate,
+				RateFactor: hevc.HighQuality,
+			}
+			profiles = append(profiles, proxyProfiles(master)...)
+		}
+	}
+
+	// HDR profiles
+	hdrResolutions := []*video.Resolution{
+		{Width: 1920, Height: 1080},
+		{Width: 3840, Height: 2160},
+	}
+	hdrFramerates := []video.FrameRate{video.FPS23976, video.FPS25}
+	dynamicRanges := []hevc.DynamicRange{hevc.HDR10, hevc.HDR10Plus, hevc.HLG, hevc.DolbyVision81}
+	for _, resolution := range hdrResolutions {
+		for _, framerate := range hdrFramerates {
+			for _, dynamicRange := range dynamicRanges {
+				for _, quality := range qualities {
+					profile := &hevc.EncodeProfile{
+						Source:       fmt.Sprintf("hdr %dx%d %vfps %s crf%v", resolution.Width, resolution.Height, framerate, dynamicRange.Label(), quality),
+						Width:        resolution.Width,
+						Height:       resolution.Height,
+						FrameRate:    framerate,
+						DynamicRange: dynamicRange,
+						RateFactor:   quality,
+					}
+					if dynamicRange.IsPQ() {
+						profile.MasteringDisplay = hevc.DisplayP3D65
+						profile.ContentLight = hevc.ContentLightLevel{MaxCLL: 1000, MaxFALL: 400}
+					}
+					profiles = append(profiles, profile)
+				}
+			}
+		}
+	}
+
+	// Profile matching a quality target replaces the profile matrix
+	if *target != "" {
+		profile, err := matchedProfile(*target, *size, frameRate)
+		if err != nil {
+			logger.Error(err, "invalid argument")
+			os.Exit(1)
+		}
+		profiles = []*hevc.EncodeProfile{profile}
+	}
+
+	// Cap CRF profiles by VBV at the maximum of their level, unless opted out
+	if !*uncapped {
+		for _, profile := range profiles {
+			profile.RateControl = profile.RateControl.Capped()
+		}
+	}
+
+	// Let Hybrid adjust the colors of SDR profiles to the input, if requested
+	if *colorFromInput {
+		for _, profile := range profiles {
+			profile.ColorFromInput = true
+		}
+	}
+
+	// Skip profiles that exceed the highest level
+	supportedProfiles := []*hevc.EncodeProfile{}
+	for _, profile := range profiles {
+		if hevc.MinLevel(profile.Width, profile.Height, profile.LevelFrameRate(), profile.ScanType) == 0 {
+			logger.Warnf("no HEVC level supports profile %s", profile.Source)
+			continue
+		}
+		supportedProfiles = append(supportedProfiles, profile)
+	}
+	profiles = supportedProfiles
+
+	// Skip profiles whose estimated encoder memory exceeds the budget
+	allParams := []*EncodeParams{}
+	budgetProfiles := []*hevc.EncodeProfile{}
+	for _, profile := range profiles {
+		params := createSetting(profile, targetHost)
+		if budget > 0 && uint64(params.EncoderMemoryMiB) > budget {
+			logger.Warnf("estimated encoder memory %d MiB of %s exceeds budget of %d MiB", params.EncoderMemoryMiB, params.Name, budget)
+			continue
+		}
+		allParams = append(allParams, params)
+		budgetProfiles = append(budgetProfiles, profile)
+	}
+	profiles = budgetProfiles
+
+	profileManifest := manifest.New("x265", *format)
+	if *manifestTime {
+		profileManifest.Stamp(time.Now())
+	}
+	switch *format {
+	case "hybrid":
+		template := loadTemplate()
+		for i, params := range allParams {
+			fileName, content := saveSetting(template, params)
+			if fileName != "" {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, templateFile, content, params)
+			}
+		}
+	case "shell", "json":
+		for i, params := range allParams {
+			fileName, content := saveCommandLine(*format, params)
+			if fileName != "" {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, "", content, params)
+			}
+		}
+	case "ffmpeg", "ffpreset":
+		for i, params := range allParams {
+			fileName, content := saveFFmpeg(*format, params)
+			if fileName != "" {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, "", content, params)
+			}
+		}
+	case "handbrake":
+		fileName, content := saveHandBrake(allParams)
+		if fileName != "" {
+			for i, params := range allParams {
+				profileManifest.Add(params.Name, fileName, profiles[i].Source, "", content, params)
+			}
+		}
+	default:
+		logger.Error(fmt.Errorf("unknown output format %q", *format), "invalid argument")
+		os.Exit(1)
+	}
+	err = profileManifest.Save("x265 manifest")
+	if err != nil {
+		logger.Error(err, "cannot write manifest")
+	}
+	logger.Info("x265 profiles generated successfully.")
+}
+
+// Read the Hybrid template and validate it against EncodeParams.
+// Exit immediately if the template is unusable.
+func loadTemplate() *template.Template {
+	defaultProfile, err := ioutil.ReadFile(templateFile)
 	if err != nil {
 		logger.Error(err, "failed to read template file")
+		os.Exit(1)
 	}
 	template, err := template.New("x265").Parse(string(defaultProfile))
 	if err != nil {
 		logger.Error(err, "failed to parse profile")
+		os.Exit(1)
 	}
-	for _, profile := range profiles {
-		params := createSetting(profile)
-		saveSetting(template, params)
+	unused, err := hybrid.Validate(template, EncodeParams{})
+	if err != nil {
+		logger.Error(err, "invalid template")
+		os.Exit(1)
 	}
-	logger.Info("x265 profiles generated successfully.")
+	for _, field := range unused {
+		logger.Warnf("parameter %s is not used by template %s", field, template.Name())
+	}
+	return template
+}
+
+// Return the profile reaching the quality target at the size and frame rate, named after the target,
+// whose rate factor is equivalent to the reference rate factor of the target.
+// Profiles of all codecs matching the same target, size and frame rate have the same name.
+func matchedProfile(targetName, size string, frameRate video.FrameRate) (*hevc.EncodeProfile, error) {
+	target, err := encode.FindQualityTarget(targetName)
+	if err != nil {
+		return nil, err
+	}
+	width, height, err := video.ParseSize(size)
+	if err != nil {
+		return nil, err
+	}
+	rateFactor := hevc.EquivalentRateFactor(target.Reference)
+	return &hevc.EncodeProfile{
+		Name:       fmt.Sprintf("%dx%d@%s-%s", width, height, frameRate.Label(), target.Name),
+		Source:     fmt.Sprintf("match %s %dx%d %vfps crf%v", target.Name, width, height, frameRate, rateFactor),
+		Width:      width,
+		Height:     height,
+		FrameRate:  frameRate,
+		RateFactor: rateFactor,
+	}, nil
+}
+
+// Return names of all quality targets separated by comma.
+func qualityTargetNames() string {
+	names := []string{}
+	for _, target := range encode.QualityTargets {
+		names = append(names, target.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Return the proxy profiles of the master profile at all proxy sizes, named after the master profile.
+// Proxies are 8-bit 4:2:0 with preview quality and edit GOP, and keep the color description of the master.
+func proxyProfiles(master *hevc.EncodeProfile) []*hevc.EncodeProfile {
+	masterResolution := &video.Resolution{
+		Width:        master.Width,
+		Height:       master.Height,
+		SampleAspect: master.SampleAspect,
+		FrameRate:    master.FrameRate,
+		ScanType:     master.ScanType,
+		FieldOrder:   master.FieldOrder,
+	}
+	proxies := []*hevc.EncodeProfile{}
+	for _, size := range []encode.ProxySize{encode.ProxyHalf, encode.ProxyQuarter} {
+		resolution := size.Resolution(masterResolution)
+		proxies = append(proxies, &hevc.EncodeProfile{
+			Name:          profileName(master) + " " + size.Label(),
+			Source:        fmt.Sprintf("proxy %dx%d of %s", resolution.Width, resolution.Height, profileName(master)),
+			Width:         resolution.Width,
+			Height:        resolution.Height,
+			SampleAspect:  resolution.SampleAspect,
+			FrameRate:     master.FrameRate,
+			PeakFrameRate: master.PeakFrameRate,
+			ScanType:      master.ScanType,
+			FieldOrder:    master.FieldOrder,
+			RateFactor:    hevc.PreviewQuality,
+			GOPMode:       encode.ProxyGOPMode,
+			Color:         master.ColorDescription(),
+		})
+	}
+	return proxies
+}
+
+// Return the name of EncodeProfile, which is generated from its properties if not defined.
+func profileName(profile *hevc.EncodeProfile) string {
+	if profile.Name != "" {
+		return profile.Name
+	}
+	quality := profile.RateFactor.Label()
+	if profile.Lossless {
+		quality = "LL"
+	} else if profile.RateControl.Mode.IsBitRate() {
+		quality = profile.RateControl.Label()
+	}
+	frameRate := profile.FrameRate.Label()
+	if profile.IsVariableFrameRate() {
+		frameRate += "vfr" + profile.PeakFrameRate.Label()
+	}
+	name := fmt.Sprintf("%dx%d%s@%s-%s", profile.Width, profile.Height, profile.ScanType.Label(profile.FieldOrder), frameRate, quality)
+	if formatLabel := video.FormatLabel(profile.BitDepth, profile.ChromaFormat); formatLabel != "" {
+		name += "-" + formatLabel
+	}
+	if profile.DynamicRange.IsHDR() {
+		name += "-" + profile.DynamicRange.Label()
+	}
+	if gopLabel := profile.GOPMode.Label(); gopLabel != "" {
+		name += "-" + gopLabel
+	}
+	if !profile.Lossless && profile.RateControl.Mode == encode.CappedCRF && profile.RateControl.BitRate > 0 {
+		name += "-" + profile.RateControl.Label()
+	}
+	return name
 }
 
 // Create EncodeParams based on EncodeProfile.
-func createSetting(profile *hevc.EncodeProfile) *EncodeParams {
-	quality := "L"
+func createSetting(profile *hevc.EncodeProfile, targetHost *host.Host) *EncodeParams {
 	qualityMultiplier := float64(1)
-	if float64(profile.RateFactor) <= float64(19) {
-		quality = "X"
-		qualityMultiplier = float64(1)
-	} else if float64(profile.RateFactor) <= float64(24) {
-		quality = "H"
+	if !profile.Lossless && profile.RateFactor.TierIndex() == 1 {
 		qualityMultiplier = float64(2)
 	}
 	params := &EncodeParams{
-		Name:       opx.Ternary(profile.Name != "", profile.Name, fmt.Sprintf("%dx%d@%4.2f-%s", profile.Width, profile.Height, profile.FrameRate, quality)),
-		Width:      profile.Width,
-		Height:     profile.Height,
-		FrameRate:  profile.FrameRate,
-		RateFactor: float64(profile.RateFactor),
-	}
-	level := hevc.MinLevel(profile.Width, profile.Height, profile.FrameRate)
-	meRange, minLevel, threadCount, aqStrength := factorsByResolution(profile.Width)
+		Name:              profileName(profile),
+		Width:             profile.Width,
+		Height:            profile.Height,
+		FrameRate:         profile.FrameRate,
+		PeakFrameRate:     profile.LevelFrameRate(),
+		VariableFrameRate: profile.IsVariableFrameRate(),
+		RateFactor:        float64(profile.RateFactor),
+	}
+	level := hevc.MinLevel(profile.Width, profile.Height, profile.LevelFrameRate(), profile.ScanType)
+	meRange, minLevel, threadCount, aqStrength := factorsByResolution(profile.Width, profile.Height)
 	level = mathxt.MaxUint8(level, minLevel)
-	refFrame, bFrame, aqStrengthModifier := factorsByRateFactor(profile.RateFactor, profile.FrameRate*qualityMultiplier)
+	hevcProfile := hevc.ProfileName(profile.OutputBitDepth(), profile.ChromaFormat)
+	if profile.Lossless {
+		// lossless bitrate usually exceeds all levels, level none allows the stream to be non-conforming
+		bitRate := encode.LosslessBitRate(profile.Width, profile.Height, profile.LevelFrameRate(), profile.OutputBitDepth(), profile.ChromaFormat)
+		level = hevc.MinLevelByBitRate(level, bitRate, hevcProfile)
+	} else if bitRate := profile.RateControl.RequestedBitRate(); bitRate > 0 {
+		// requested bitrate may exceed the level chosen by frame size and rate
+		if bitRateLevel := hevc.MinLevelByBitRate(level, uint64(bitRate), hevcProfile); bitRateLevel != 0 {
+			level = bitRateLevel
+		} else {
+			logger.Warnf("requested bitrate %d kbps of %s exceeds all HEVC levels", bitRate, params.Name)
+		}
+	}
+	refFrame, bFrame, aqStrengthModifier := factorsByRateFactor(profile.RateFactor, profile.FrameRate.Float64()*qualityMultiplier)
 
 	params.ThreadCount = threadCount
-	params.RateFactorMax = float64(profile.RateFactor) - 5
+	applyThreads(params, profile, targetHost)
 	params.HEVCLevel = float64(level) / 10
-	params.HEVCTier = opx.Ternary(level >= 40, "High", "Main")
+	params.HEVCTier = opx.Ternary(hevc.IsHighTier(level), "High", "Main")
+	params.AllowNonConformance = level == 0
 	params.RefFrame = refFrame
 	params.MeRange = meRange
-	params.BFrame = bFrame
-	params.KeyInterval = uint16(math.Ceil(profile.FrameRate) * 10)
-	params.RCLookahead = mathxt.MinUint16(uint16(math.Ceil(profile.FrameRate)*2), 120)
+	params.BFrame = mathxt.MinUint8(bFrame, profile.GOPMode.MaxBFrames())
+	params.KeyInterval = uint16(profile.GOPMode.KeyInterval(profile.FrameRate))
+	params.KeyIntervalMin = uint16(profile.GOPMode.MinKeyInterval(profile.FrameRate))
+	params.SceneCut = opx.Ternary(profile.GOPMode.IsEdit(), 0, uint8(40))
+	params.FastDecode = profile.GOPMode.IsEdit()
+	params.Lossless = profile.Lossless
+	params.PsyRD = opx.Ternary(profile.Lossless, 0, float64(2))
+	params.PsyRDOQ = opx.Ternary(profile.Lossless, 0, float64(1))
+	params.RCLookahead = mathxt.MinUint16(uint16(profile.FrameRate.Frames(2)), 120)
 	params.AQStrength = aqStrength + aqStrengthModifier
+	params.SampleAspect = opx.Ternary(profile.SampleAspect.IsZero(), video.SquarePixel, profile.SampleAspect)
+	params.DisplayAspect = video.DisplayAspect(profile.Width, profile.Height, profile.SampleAspect)
+	params.Interlaced = profile.ScanType == video.Interlaced
+	params.FieldOrder = opx.Ternary(profile.FieldOrder == video.BottomFieldFirst, "bff", "tff")
+	params.PicStruct = picStruct(profile.ScanType, profile.FieldOrder)
+	params.BitDepth = profile.OutputBitDepth()
+	params.HEVCProfile = hevcProfile
+	params.OutputColorSpace = profile.ChromaFormat.ColorSpace()
+	params.PixelFormat = video.PixelFormat(params.BitDepth, profile.ChromaFormat)
+	params.AutoFormat = params.BitDepth == 8 && profile.ChromaFormat == video.Chroma420
+	color := profile.ColorDescription()
+	params.VUIColorPrimes = color.Primaries
+	params.VUITransfer = color.Transfer
+	params.VUIColorMatrix = color.Matrix
+	params.VUIRange = color.Range
+	params.VUIChromaLocation = color.ChromaLocation
+	applyRateControl(params, profile, level)
+	applyDynamicRange(params, profile)
+	params.EncoderMemoryMiB, params.DecoderMemoryMiB = hevc.EstimateMemory(profile.Width, profile.Height, profile.ScanType, params.BitDepth, profile.ChromaFormat,
+		params.RefFrame, params.BFrame, params.RCLookahead, params.FrameThreads, params.ThreadCount)
 	return params
 }
 
+// Apply the threading of the host to EncodeParams, without host the threads chosen by resolution are kept
+// in a single pool and x265 chooses frame threads. With host, all logical cores of the host are worker threads,
+// in one pool per NUMA node, and frame threads are limited by CTU rows of the picture.
+func applyThreads(params *EncodeParams, profile *hevc.EncodeProfile, targetHost *host.Host) {
+	params.Pools = fmt.Sprint(params.ThreadCount)
+	params.WavefrontPP = true
+	if targetHost == nil {
+		return
+	}
+
+	threads := max(targetHost.LogicalCores, 1)
+	params.ThreadCount = threads
+	params.Pools = hevc.Pools(threads, targetHost.NUMANodes, targetHost.CoresPerNode())
+	params.FrameThreads = hevc.FrameThreads(threads, profile.Height, profile.ScanType)
+	params.LookaheadThreads = hevc.LookaheadThreads(threads)
+	params.WavefrontPP = threads > 1
+}
+
+// Apply the rate control of EncodeProfile to EncodeParams.
+// Bitrate and VBV not requested by the profile are the maximum of the level and tier,
+// lossless coding and level none ignore rate control.
+// Dolby Vision requires VBV, so its CRF profiles are always capped.
+func applyRateControl(params *EncodeParams, profile *hevc.EncodeProfile, level uint8) {
+	rateControl := profile.RateControl
+	if profile.DynamicRange == hevc.DolbyVision81 {
+		rateControl = rateControl.Capped()
+	}
+	if profile.Lossless || level == 0 {
+		rateControl = encode.RateControl{}
+	}
+	var levelBitRate, levelCPBSize uint32
+	if x265Profile := hevc.ProfileByLevel(level); x265Profile != nil {
+		highTier := hevc.IsHighTier(level)
+		levelBitRate = x265Profile.MaxBitRate(params.HEVCProfile, highTier)
+		levelCPBSize = x265Profile.MaxCPBSize(params.HEVCProfile, highTier)
+	}
+	params.EncodingType = hybrid.EncodingType(rateControl.Mode)
+	params.Passes = rateControl.Mode.Passes()
+	params.BitRate = rateControl.AverageBitRate(levelBitRate)
+	params.TargetSize = rateControl.TargetSize
+	params.PreferTargetSize = rateControl.Mode == encode.TargetSize
+	params.FastFirstPass = params.Passes > 1
+	params.VBVMaxBitRate, params.VBVBufferSize = rateControl.VBV(levelBitRate, levelCPBSize)
+	if rateControl.Mode == encode.CappedCRF {
+		params.RateFactorMax = params.RateFactor + encode.CRFMaxOffset
+	}
+	params.StrictCBR = rateControl.Mode == encode.CBR
+}
+
+// Apply the HDR format of EncodeProfile to EncodeParams.
+// Main 10 profile and BT.2020 colors required by HDR are set by bit depth and color description,
+// so only SDR video adjusts colors to the input when requested.
+// PQ formats are optimized with hdr10-opt and AQ mode 3, which biases to dark scenes.
+func applyDynamicRange(params *EncodeParams, profile *hevc.EncodeProfile) {
+	params.AQMode = 4
+	params.DolbyVisionProfile = "none"
+	params.AdjustVUIToInput = profile.ColorFromInput && !profile.DynamicRange.IsHDR()
+	if !profile.DynamicRange.IsHDR() {
+		return
+	}
+
+	params.SignalHLG = profile.DynamicRange == hevc.HLG
+	if profile.DynamicRange.IsPQ() {
+		params.AQMode = 3
+		params.SignalHDR = true
+		params.HDROpt = true
+		params.MasterDisplay = profile.MasteringDisplay.String()
+		params.MaxCLL = profile.ContentLight.MaxCLL
+		params.MaxFALL = profile.ContentLight.MaxFALL
+	}
+	switch profile.DynamicRange {
+	case hevc.HDR10Plus:
+		params.DynamicMetadataFile = profile.MetadataFile
+	case hevc.DolbyVision81:
+		params.DolbyVisionProfile = "8.1"
+		params.DolbyVisionRpuFile = profile.MetadataFile
+	}
+}
+
 // Save the EnodeParms to disk.
-func saveSetting(template *template.Template, params *EncodeParams) {
+// Return the file name and content written, or empty file name on failure.
+func saveSetting(template *template.Template, params *EncodeParams) (string, []byte) {
 	fileName := fmt.Sprintf("x265 %s.xml", params.Name)
 
-	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	buffer := &bytes.Buffer{}
+	err := template.Execute(buffer, params)
+	if err != nil {
+		logger.Error(err, fileName)
+		return "", nil
+	}
+	err = os.WriteFile(fileName, buffer.Bytes(), 0644)
 	if err != nil {
 		logger.Error(err, "cannot write to file", fileName)
-		return
+		return "", nil
 	}
-	defer file.Close()
+	return fileName, buffer.Bytes()
+}
 
-	err = template.Execute(file, params)
-	if err != nil {
-		logger.Error(err, fileName)
+// Return the Hybrid label of picture structure signaled in picture timing SEI.
+func picStruct(scanType video.ScanType, fieldOrder video.FieldOrder) string {
+	if scanType != video.Interlaced {
+		return "(progressive) Frame"
 	}
+	if fieldOrder == video.BottomFieldFirst {
+		return "(interlaced) Bottom field"
+	}
+	return "(interlaced) Top field"
 }
 
-// Determine the motion estimation range and AQ strength based on the video width.
-func factorsByResolution(width uint16) (meRange, minLevel, threadCount uint8, aqStrength float64) {
+// Determine the motion estimation range and AQ strength based on the long edge of the video,
+// so portrait video is treated the same as landscape video of the same size.
+func factorsByResolution(width, height uint32) (meRange, minLevel uint8, threadCount uint16, aqStrength float64) {
 	meRange = uint8(24)
 	minLevel = uint8(10)
-	threadCount = uint8(4)
+	threadCount = uint16(4)
 	aqStrength = float64(1)
 
-	if width >= (3840 * 15 / 16) {
+	longEdge := video.LongEdge(width, height)
+	if longEdge >= (7680 * 15 / 16) {
+		meRange = uint8(92)
+		minLevel = uint8(61)
+		threadCount = uint16(64)
+		aqStrength = float64(0.4)
+	} else if longEdge >= (3840 * 15 / 16) {
 		meRange = uint8(57)
 		minLevel = uint8(51)
-		threadCount = uint8(32)
+		threadCount = uint16(32)
 		aqStrength = float64(0.5)
-	} else if width >= (2560 * 15 / 16) {
+	} else if longEdge >= (2560 * 15 / 16) {
 		meRange = uint8(57)
 		minLevel = uint8(50)
-		threadCount = uint8(24)
+		threadCount = uint16(24)
 		aqStrength = float64(0.6)
-	} else if width >= (1920 * 7 / 8) {
+	} else if longEdge >= (1920 * 7 / 8) {
 		meRange = uint8(57)
 		minLevel = uint8(40)
-		threadCount = uint8(16)
+		threadCount = uint16(16)
 		aqStrength = float64(0.7)
-	} else if width >= (1280 * 7 / 8) {
+	} else if longEdge >= (1280 * 7 / 8) {
 		meRange = uint8(48)
 		minLevel = uint8(30)
-		threadCount = uint8(12)
+		threadCount = uint16(12)
 		aqStrength = float64(0.9)
 	} else {
 		meRange = uint8(32)
 		minLevel = uint8(20)
-		threadCount = uint8(8)
+		threadCount = uint16(8)
 		aqStrength = float64(0.9)
 	}
 
 	return meRange, minLevel, threadCount, aqStrength
 }
 
-// Determine the reference frame count, B-frame count, and AQ strength modifier based on the rate factor and frame rate.
+// Determine the reference frame count, B-frame count, and AQ strength modifier based on the quality tier of the rate factor and frame rate.
 func factorsByRateFactor(quality hevc.RateFactor, frameRate float64) (refFrame, bFrame uint8, aqStrengthModifier float64) {
 	refFrame = opx.Ternary(frameRate >= 32, uint8(4), uint8(3))
 	bFrame = uint8(7)
 	aqStrengthModifier = float64(0.15)
 
-	if float64(quality) <= float64(17) {
+	switch quality.TierIndex() {
+	case 0:
 		refFrame += 2
 		bFrame = uint8(12)
 		aqStrengthModifier = float64(0)
-	} else if float64(quality) <= float64(22) {
+	case 1:
 		refFrame += 1
 		bFrame = uint8(10)
 		aqStrengthModifier = float64(0.05)
-	} else {
+	default:
 		refFrame += 0
 		bFrame = uint8(7)
 		aqStrengthModifier = float64(0.1)
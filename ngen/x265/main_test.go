@@ -0,0 +1,523 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/lukaz17/hybrid-profile-generator-go/encode"
+	"github.com/lukaz17/hybrid-profile-generator-go/hevc"
+	"github.com/lukaz17/hybrid-profile-generator-go/host"
+	"github.com/lukaz17/hybrid-profile-generator-go/hybrid"
+	"github.com/lukaz17/hybrid-profile-generator-go/video"
+)
+
+func TestTemplateMatchesEncodeParams(t *testing.T) {
+	content, err := os.ReadFile("../../presets/x265.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("x265").Parse(string(content)))
+	unused, err := hybrid.Validate(tmpl, EncodeParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	if !reflect.DeepEqual(unused, want) {
+		t.Errorf("unused parameters = %v, want %v", unused, want)
+	}
+}
+
+func TestCreateSettingFrameRate(t *testing.T) {
+	tests := []struct {
+		frameRate       video.FrameRate
+		wantName        string
+		wantKeyInterval uint16
+		wantRCLookahead uint16
+	}{
+		{video.FPS23976, "1920x1080@23.976-H", 240, 48},
+		{video.FPS25, "1920x1080@25.00-H", 250, 50},
+		{video.FPS29970, "1920x1080@29.97-H", 300, 60},
+		{video.FPS59940, "1920x1080@59.94-H", 600, 120},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: tt.frameRate, RateFactor: hevc.HighQuality}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.KeyInterval != tt.wantKeyInterval || params.RCLookahead != tt.wantRCLookahead {
+				t.Errorf("KeyInterval, RCLookahead = %d, %d, want %d, %d", params.KeyInterval, params.RCLookahead, tt.wantKeyInterval, tt.wantRCLookahead)
+			}
+		})
+	}
+}
+
+func TestCreateSettingVariableFrameRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		frameRate     video.FrameRate
+		peakFrameRate video.FrameRate
+		wantName      string
+		wantVariable  bool
+		wantLevel     uint8
+	}{
+		{"constant", video.FPS30, video.FrameRate{}, "1920x1080@30.00-H", false, hevc.MinLevel(1920, 1080, video.FPS30, video.Progressive)},
+		{"nominal 30 peak 60", video.FPS30, video.FPS60, "1920x1080@30.00vfr60.00-H", true, hevc.MinLevel(1920, 1080, video.FPS60, video.Progressive)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: tt.frameRate, PeakFrameRate: tt.peakFrameRate, RateFactor: hevc.HighQuality}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.VariableFrameRate != tt.wantVariable {
+				t.Errorf("VariableFrameRate = %v, want %v", params.VariableFrameRate, tt.wantVariable)
+			}
+			if want := float64(tt.wantLevel) / 10; params.HEVCLevel < want {
+				t.Errorf("HEVCLevel = %v, want at least %v", params.HEVCLevel, want)
+			}
+			// GOP length follows the nominal frame rate
+			if params.KeyInterval != 300 {
+				t.Errorf("KeyInterval = %d, want 300", params.KeyInterval)
+			}
+			// timecodes of variable frame rate are applied when muxing, so VUI timing is not signaled
+			args := createCommandLine(params).Args()
+			if slices.Contains(args, "--no-vui-timing-info") != tt.wantVariable {
+				t.Errorf("Args() = %q, want --no-vui-timing-info %v", args, tt.wantVariable)
+			}
+		})
+	}
+}
+
+func TestCreateSettingScanType(t *testing.T) {
+	tests := []struct {
+		name           string
+		resolution     video.Resolution
+		wantName       string
+		wantInterlaced bool
+		wantPicStruct  string
+		wantArgs       []string
+	}{
+		{"progressive", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS25}, "1920x1080@25.00-H", false, "(progressive) Frame", nil},
+		{"progressive ignores field order", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS25, FieldOrder: video.BottomFieldFirst}, "1920x1080@25.00-H", false, "(progressive) Frame", nil},
+		{"interlaced tff", video.Resolution{Width: 1920, Height: 1080, FrameRate: video.FPS25, ScanType: video.Interlaced}, "1920x1080i@25.00-H", true, "(interlaced) Top field", []string{"--interlace", "tff"}},
+		{"interlaced bff", video.Resolution{Width: 720, Height: 480, FrameRate: video.FPS29970, ScanType: video.Interlaced, FieldOrder: video.BottomFieldFirst}, "720x480ib@29.97-H", true, "(interlaced) Bottom field", []string{"--interlace", "bff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{
+				Width:      tt.resolution.Width,
+				Height:     tt.resolution.Height,
+				FrameRate:  tt.resolution.FrameRate,
+				ScanType:   tt.resolution.ScanType,
+				FieldOrder: tt.resolution.FieldOrder,
+				RateFactor: hevc.HighQuality,
+			}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.Interlaced != tt.wantInterlaced || params.PicStruct != tt.wantPicStruct {
+				t.Errorf("Interlaced, PicStruct = %v, %q, want %v, %q", params.Interlaced, params.PicStruct, tt.wantInterlaced, tt.wantPicStruct)
+			}
+			args := createCommandLine(params).Args()
+			if !tt.wantInterlaced {
+				if slices.Contains(args, "--interlace") {
+					t.Errorf("Args() = %q, want no --interlace", args)
+				}
+				return
+			}
+			if tail := args[len(args)-2:]; !reflect.DeepEqual(tail, tt.wantArgs) {
+				t.Errorf("scan arguments = %q, want %q", tail, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFactorsByResolution(t *testing.T) {
+	tests := []struct {
+		name            string
+		width           uint32
+		height          uint32
+		wantMeRange     uint8
+		wantMinLevel    uint8
+		wantThreadCount uint16
+		wantAQStrength  float64
+	}{
+		{"720p", 1280, 720, 48, 30, 12, 0.9},
+		{"720p portrait", 720, 1280, 48, 30, 12, 0.9},
+		{"1080p", 1920, 1080, 57, 40, 16, 0.7},
+		{"1080p portrait", 1080, 1920, 57, 40, 16, 0.7},
+		{"1440p", 2560, 1440, 57, 50, 24, 0.6},
+		{"2160p", 3840, 2160, 57, 51, 32, 0.5},
+		{"2160p portrait", 2160, 3840, 57, 51, 32, 0.5},
+		{"4320p", 7680, 4320, 92, 61, 64, 0.4},
+		{"4320p portrait", 4320, 7680, 92, 61, 64, 0.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meRange, minLevel, threadCount, aqStrength := factorsByResolution(tt.width, tt.height)
+			if meRange != tt.wantMeRange || minLevel != tt.wantMinLevel || threadCount != tt.wantThreadCount || aqStrength != tt.wantAQStrength {
+				t.Errorf("factorsByResolution(%d, %d) = %d, %d, %d, %v, want %d, %d, %d, %v", tt.width, tt.height, meRange, minLevel, threadCount, aqStrength, tt.wantMeRange, tt.wantMinLevel, tt.wantThreadCount, tt.wantAQStrength)
+			}
+		})
+	}
+}
+
+func TestCreateSettingFormat(t *testing.T) {
+	tests := []struct {
+		name            string
+		bitDepth        uint8
+		chromaFormat    video.ChromaFormat
+		wantName        string
+		wantProfile     string
+		wantPixelFormat string
+		wantArgs        []string
+	}{
+		{"8-bit 4:2:0", 8, video.Chroma420, "1920x1080@25.00-X", "Main", "yuv420p", nil},
+		{"10-bit 4:2:0", 10, video.Chroma420, "1920x1080@25.00-X-10b", "Main10", "yuv420p10le", []string{"--profile", "main10", "--output-depth", "10"}},
+		{"10-bit 4:2:2", 10, video.Chroma422, "1920x1080@25.00-X-10b422", "Main422-10", "yuv422p10le", []string{"--profile", "main422-10", "--output-depth", "10", "--input-csp", "i422"}},
+		{"8-bit 4:4:4", 8, video.Chroma444, "1920x1080@25.00-X-8b444", "Main444-8", "yuv444p", []string{"--profile", "main444-8", "--input-csp", "i444"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, BitDepth: tt.bitDepth, ChromaFormat: tt.chromaFormat, RateFactor: hevc.UltraQuality}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.HEVCProfile != tt.wantProfile || params.PixelFormat != tt.wantPixelFormat {
+				t.Errorf("HEVCProfile/PixelFormat = %s/%s, want %s/%s", params.HEVCProfile, params.PixelFormat, tt.wantProfile, tt.wantPixelFormat)
+			}
+			if autoFormat := tt.bitDepth == 8 && tt.chromaFormat == video.Chroma420; params.AutoFormat != autoFormat {
+				t.Errorf("AutoFormat = %v, want %v", params.AutoFormat, autoFormat)
+			}
+			// format options follow the level and tier, and precede the rate factor
+			args := createCommandLine(params).Args()
+			if i := slices.Index(args, "--crf"); i < 3 || !slices.Equal(args[3:i], tt.wantArgs) {
+				t.Errorf("Args() = %q, want format arguments %q", args, tt.wantArgs)
+			}
+			// FFmpeg selects the pixel format only when it is not the default
+			ffmpegArgs := createFFmpeg(params).Args()
+			if i := slices.Index(ffmpegArgs, "-pix_fmt"); params.AutoFormat != (i < 0) || (i >= 0 && ffmpegArgs[i+1] != tt.wantPixelFormat) {
+				t.Errorf("FFmpeg Args() = %q, want pix_fmt %q unless AutoFormat", ffmpegArgs, tt.wantPixelFormat)
+			}
+		})
+	}
+}
+
+func TestCreateSettingLossless(t *testing.T) {
+	tests := []struct {
+		name          string
+		width         uint32
+		height        uint32
+		bitDepth      uint8
+		chromaFormat  video.ChromaFormat
+		gopMode       encode.GOPMode
+		wantName      string
+		wantLevel     float64
+		wantLevelArgs []string
+	}{
+		{"1080p intra-only", 1920, 1080, 8, video.Chroma420, encode.IntraOnly, "1920x1080@25.00-LL-intra", 6.1, []string{"--level-idc", "6.1", "--high-tier"}},
+		{"1080p 10-bit 4:2:2 short GOP", 1920, 1080, 10, video.Chroma422, encode.ShortGOP, "1920x1080@25.00-LL-10b422-sgop", 6.1, []string{"--level-idc", "6.1", "--high-tier"}},
+		{"2160p 10-bit 4:2:2 exceeds all levels", 3840, 2160, 10, video.Chroma422, encode.IntraOnly, "3840x2160@25.00-LL-10b422-intra", 0, []string{"--allow-non-conformance", "--no-high-tier"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: tt.width, Height: tt.height, FrameRate: video.FPS25, BitDepth: tt.bitDepth, ChromaFormat: tt.chromaFormat, Lossless: true, GOPMode: tt.gopMode}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.HEVCLevel != tt.wantLevel || params.AllowNonConformance != (tt.wantLevel == 0) {
+				t.Errorf("HEVCLevel/AllowNonConformance = %v/%v, want %v/%v", params.HEVCLevel, params.AllowNonConformance, tt.wantLevel, tt.wantLevel == 0)
+			}
+			if params.PsyRD != 0 || params.PsyRDOQ != 0 {
+				t.Errorf("PsyRD/PsyRDOQ = %v/%v, want 0/0", params.PsyRD, params.PsyRDOQ)
+			}
+			args := createCommandLine(params).Args()
+			if !slices.Equal(args[:len(tt.wantLevelArgs)], tt.wantLevelArgs) {
+				t.Errorf("Args() = %q, want prefix %q", args, tt.wantLevelArgs)
+			}
+			if !slices.Contains(args, "--lossless") || slices.Contains(args, "--crf") {
+				t.Errorf("Args() = %q, want --lossless without --crf", args)
+			}
+		})
+	}
+}
+
+func TestCreateSettingGOPMode(t *testing.T) {
+	tests := []struct {
+		gopMode         encode.GOPMode
+		wantName        string
+		wantBFrame      uint8
+		wantKeyInterval uint16
+		wantGOPArgs     []string
+	}{
+		{encode.LongGOP, "1920x1080@25.00-H", 10, 250, []string{"--keyint", "250", "--rc-lookahead"}},
+		{encode.EditGOP, "1920x1080@25.00-H-edit", 2, 25, []string{"--keyint", "25", "--min-keyint", "25", "--no-scenecut", "--rc-lookahead"}},
+		{encode.EditIntra, "1920x1080@25.00-H-editintra", 0, 1, []string{"--keyint", "1", "--min-keyint", "1", "--no-scenecut", "--rc-lookahead"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: hevc.HighQuality, GOPMode: tt.gopMode}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.BFrame != tt.wantBFrame || params.KeyInterval != tt.wantKeyInterval {
+				t.Errorf("BFrame/KeyInterval = %d/%d, want %d/%d", params.BFrame, params.KeyInterval, tt.wantBFrame, tt.wantKeyInterval)
+			}
+			if params.FastDecode != tt.gopMode.IsEdit() {
+				t.Errorf("FastDecode = %v, want %v", params.FastDecode, tt.gopMode.IsEdit())
+			}
+			args := createCommandLine(params).Args()
+			i := slices.Index(args, "--keyint")
+			if i < 0 || i+len(tt.wantGOPArgs) > len(args) || !slices.Equal(args[i:i+len(tt.wantGOPArgs)], tt.wantGOPArgs) {
+				t.Errorf("Args() = %q, want %q", args, tt.wantGOPArgs)
+			}
+			// edit modes replace deblocking and weighted prediction with options friendly to fast decoding
+			fastDecodeArgs := []string{"--no-deblock", "--no-weightp", "--no-weightb"}
+			j := slices.Index(args, fastDecodeArgs[0])
+			hasFastDecode := j >= 0 && j+len(fastDecodeArgs) <= len(args) && slices.Equal(args[j:j+len(fastDecodeArgs)], fastDecodeArgs)
+			if hasFastDecode != tt.gopMode.IsEdit() || slices.Contains(args, "--deblock") == tt.gopMode.IsEdit() {
+				t.Errorf("Args() = %q, want fast decode options %v", args, tt.gopMode.IsEdit())
+			}
+		})
+	}
+}
+
+func TestProxyProfiles(t *testing.T) {
+	master := &hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, BitDepth: 10, ChromaFormat: video.Chroma422, RateFactor: hevc.HighQuality}
+	tests := []struct {
+		wantName   string
+		wantWidth  uint32
+		wantHeight uint32
+	}{
+		{"1920x1080@25.00-H-10b422 proxy-half", 960, 540},
+		{"1920x1080@25.00-H-10b422 proxy-quarter", 480, 270},
+	}
+	proxies := proxyProfiles(master)
+	if len(proxies) != len(tests) {
+		t.Fatalf("len(proxyProfiles()) = %d, want %d", len(proxies), len(tests))
+	}
+	for i, tt := range tests {
+		proxy := proxies[i]
+		if proxy.Width != tt.wantWidth || proxy.Height != tt.wantHeight {
+			t.Errorf("proxy %d = %dx%d, want %dx%d", i, proxy.Width, proxy.Height, tt.wantWidth, tt.wantHeight)
+		}
+		if proxy.RateFactor != hevc.PreviewQuality || proxy.GOPMode != encode.ProxyGOPMode {
+			t.Errorf("proxy %d RateFactor/GOPMode = %v/%d, want %v/%d", i, proxy.RateFactor, proxy.GOPMode, hevc.PreviewQuality, encode.ProxyGOPMode)
+		}
+		// proxies keep the colors of the master, even when their size defaults to SD colors
+		if proxy.ColorDescription() != video.BT709 {
+			t.Errorf("proxy %d ColorDescription() = %+v, want BT.709", i, proxy.ColorDescription())
+		}
+		params := createSetting(proxy, nil)
+		if params.Name != tt.wantName {
+			t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+		}
+		if params.HEVCProfile != "Main" || params.BitDepth != 8 || !params.AutoFormat {
+			t.Errorf("HEVCProfile/BitDepth/AutoFormat = %s/%d/%v, want Main/8/true", params.HEVCProfile, params.BitDepth, params.AutoFormat)
+		}
+	}
+}
+
+func TestCreateSettingRateControl(t *testing.T) {
+	tests := []struct {
+		name             string
+		rateControl      encode.RateControl
+		wantName         string
+		wantLevel        float64
+		wantEncodingType string
+		wantPasses       uint8
+		wantRateArgs     []string
+	}{
+		{"crf", encode.RateControl{}, "1920x1080@25.00-H", 4.0, "constant rate factor (1-pass)", 1, []string{"--crf", "21.0"}},
+		{"capped crf", encode.RateControl{Mode: encode.CappedCRF}, "1920x1080@25.00-H", 4.0, "constant rate factor (1-pass)", 1, []string{"--crf", "21.0", "--crf-max", "26.0", "--vbv-maxrate", "30000", "--vbv-bufsize", "30000"}},
+		{"capped crf at bitrate", encode.RateControl{Mode: encode.CappedCRF, BitRate: 5000}, "1920x1080@25.00-H-ccrf5000", 4.0, "constant rate factor (1-pass)", 1, []string{"--crf", "21.0", "--crf-max", "26.0", "--vbv-maxrate", "5000", "--vbv-bufsize", "5000"}},
+		{"abr", encode.RateControl{Mode: encode.ABR, BitRate: 5000}, "1920x1080@25.00-abr5000", 4.0, "average bitrate (1-pass)", 1, []string{"--bitrate", "5000"}},
+		{"2 pass", encode.RateControl{Mode: encode.TwoPassABR, BitRate: 5000}, "1920x1080@25.00-2pass5000", 4.0, "average bitrate (2-pass)", 2, []string{"--bitrate", "5000", "--no-slow-firstpass"}},
+		{"cbr", encode.RateControl{Mode: encode.CBR, BitRate: 5000}, "1920x1080@25.00-cbr5000", 4.0, "average bitrate (1-pass)", 1, []string{"--bitrate", "5000", "--vbv-maxrate", "5000", "--vbv-bufsize", "5000", "--strict-cbr", "--hrd"}},
+		{"target size", encode.RateControl{Mode: encode.TargetSize, TargetSize: 3072, Duration: 2 * time.Hour}, "1920x1080@25.00-size3072-120min", 4.0, "average bitrate (2-pass)", 2, []string{"--bitrate", "3579", "--no-slow-firstpass"}},
+		{"bitrate raises level", encode.RateControl{Mode: encode.ABR, BitRate: 40000}, "1920x1080@25.00-abr40000", 4.1, "average bitrate (1-pass)", 1, []string{"--bitrate", "40000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: hevc.HighQuality, RateControl: tt.rateControl}, nil)
+			if params.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", params.Name, tt.wantName)
+			}
+			if params.HEVCLevel != tt.wantLevel {
+				t.Errorf("HEVCLevel = %v, want %v", params.HEVCLevel, tt.wantLevel)
+			}
+			if params.EncodingType != tt.wantEncodingType || params.Passes != tt.wantPasses {
+				t.Errorf("EncodingType/Passes = %q/%d, want %q/%d", params.EncodingType, params.Passes, tt.wantEncodingType, tt.wantPasses)
+			}
+			if params.FastFirstPass != (tt.wantPasses > 1) {
+				t.Errorf("FastFirstPass = %v, want %v", params.FastFirstPass, tt.wantPasses > 1)
+			}
+			// rate control options precede the reference frames
+			args := createCommandLine(params).Args()
+			i := slices.IndexFunc(args, func(arg string) bool { return arg == "--crf" || arg == "--bitrate" })
+			j := slices.Index(args, "--ref")
+			if i < 0 || j < i || !slices.Equal(args[i:j], tt.wantRateArgs) {
+				t.Errorf("Args() = %q, want rate control arguments %q", args, tt.wantRateArgs)
+			}
+		})
+	}
+}
+
+func TestShellComment(t *testing.T) {
+	tests := []struct {
+		rateControl encode.RateControl
+		want        string
+	}{
+		{encode.RateControl{}, "x265 1920x1080@25.00-H"},
+		{encode.RateControl{Mode: encode.TwoPassABR, BitRate: 5000}, "x265 1920x1080@25.00-2pass5000, run 2 times with --pass 1 to --pass 2"},
+	}
+	for _, tt := range tests {
+		params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: hevc.HighQuality, RateControl: tt.rateControl}, nil)
+		if got := shellComment(params); got != tt.want {
+			t.Errorf("shellComment() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCreateSettingDolbyVisionCapped(t *testing.T) {
+	// Dolby Vision requires VBV, so uncapped CRF is capped at the level
+	params := createSetting(&hevc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS23976, RateFactor: hevc.HighQuality, DynamicRange: hevc.DolbyVision81}, nil)
+	if params.VBVMaxBitRate == 0 || params.VBVBufferSize == 0 || params.RateFactorMax != params.RateFactor+encode.CRFMaxOffset {
+		t.Errorf("VBVMaxBitRate/VBVBufferSize/RateFactorMax = %d/%d/%v, want capped CRF", params.VBVMaxBitRate, params.VBVBufferSize, params.RateFactorMax)
+	}
+	if params.EncodingType != "constant rate factor (1-pass)" {
+		t.Errorf("EncodingType = %q, want constant rate factor (1-pass)", params.EncodingType)
+	}
+}
+
+func TestCreateSettingQualityTier(t *testing.T) {
+	tests := []struct {
+		rateFactor hevc.RateFactor
+		wantName   string
+		wantBFrame uint8
+	}{
+		{hevc.UltraQuality, "1920x1080@25.00-X", 12},
+		{19, "1920x1080@25.00-X19", 12},
+		{19.5, "1920x1080@25.00-H19.5", 10},
+		{hevc.HighQuality, "1920x1080@25.00-H", 10},
+		{hevc.NormalQuality, "1920x1080@25.00-L", 7},
+		{30, "1920x1080@25.00-L30", 7},
+	}
+	for _, tt := range tests {
+		// the tier names the profile and selects the heuristics, so they never disagree
+		params := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: tt.rateFactor}, nil)
+		if params.Name != tt.wantName || params.BFrame != tt.wantBFrame {
+			t.Errorf("CRF %v: Name/BFrame = %q/%d, want %q/%d", tt.rateFactor, params.Name, params.BFrame, tt.wantName, tt.wantBFrame)
+		}
+	}
+}
+
+func TestMatchedProfile(t *testing.T) {
+	tests := []struct {
+		target         string
+		size           string
+		wantName       string
+		wantRateFactor hevc.RateFactor
+		wantErr        bool
+	}{
+		{"high", "3840x2160", "3840x2160@23.976-high", 21, false},
+		{"transparent", "1920x1080", "1920x1080@23.976-transparent", hevc.UltraQuality, false},
+		{"archive-small", "1920x1080", "1920x1080@23.976-archive-small", hevc.PreviewQuality, false},
+		{"best", "1920x1080", "", 0, true},
+		{"high", "1920", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.size, func(t *testing.T) {
+			profile, err := matchedProfile(tt.target, tt.size, video.FPS23976)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchedProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// the name is shared by all codecs, the rate factor is equivalent to the x264 reference
+			if profile.Name != tt.wantName || profile.RateFactor != tt.wantRateFactor {
+				t.Errorf("Name/RateFactor = %q/%v, want %q/%v", profile.Name, profile.RateFactor, tt.wantName, tt.wantRateFactor)
+			}
+			if params := createSetting(profile, nil); params.Name != tt.wantName {
+				t.Errorf("createSetting().Name = %q, want %q", params.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCreateSettingHost(t *testing.T) {
+	tests := []struct {
+		name              string
+		host              *host.Host
+		wantThreads       uint16
+		wantPools         string
+		wantFrameThreads  uint8
+		wantLookahead     uint8
+		wantWavefrontPP   bool
+		wantPoolArguments []string
+	}{
+		{"no host", nil, 32, "32", 0, 0, true, []string{"--pools", "32"}},
+		{"dual socket", &host.Host{LogicalCores: 32, NUMANodes: 2}, 32, "16,16", 6, 4, true, []string{"--pools", "16,16", "--frame-threads", "6", "--lookahead-threads", "4"}},
+		{"more cores than threads by resolution", &host.Host{LogicalCores: 128, NUMANodes: 1}, 128, "128", 6, 16, true, []string{"--pools", "128", "--frame-threads", "6", "--lookahead-threads", "16"}},
+		{"laptop", &host.Host{LogicalCores: 8, NUMANodes: 1}, 8, "8", 3, 0, true, []string{"--pools", "8", "--frame-threads", "3"}},
+		{"single core", &host.Host{LogicalCores: 1, NUMANodes: 1}, 1, "1", 1, 0, false, []string{"--pools", "1", "--frame-threads", "1", "--no-wpp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createSetting(&hevc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS25, RateFactor: hevc.HighQuality}, tt.host)
+			if params.ThreadCount != tt.wantThreads || params.Pools != tt.wantPools || params.FrameThreads != tt.wantFrameThreads ||
+				params.LookaheadThreads != tt.wantLookahead || params.WavefrontPP != tt.wantWavefrontPP {
+				t.Errorf("ThreadCount/Pools/FrameThreads/LookaheadThreads/WavefrontPP = %d/%q/%d/%d/%v, want %d/%q/%d/%d/%v",
+					params.ThreadCount, params.Pools, params.FrameThreads, params.LookaheadThreads, params.WavefrontPP,
+					tt.wantThreads, tt.wantPools, tt.wantFrameThreads, tt.wantLookahead, tt.wantWavefrontPP)
+			}
+			args := createCommandLine(params).Args()
+			index := slices.Index(args, "--pools")
+			if index < 0 || !slices.Equal(args[index:index+len(tt.wantPoolArguments)], tt.wantPoolArguments) {
+				t.Errorf("Args() = %v, want %v", args, tt.wantPoolArguments)
+			}
+		})
+	}
+}
+
+func TestCreateSettingMemory(t *testing.T) {
+	hd := createSetting(&hevc.EncodeProfile{Width: 1920, Height: 1080, FrameRate: video.FPS25, RateFactor: hevc.HighQuality}, nil)
+	uhd := createSetting(&hevc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS25, BitDepth: 10, RateFactor: hevc.HighQuality}, nil)
+	if hd.EncoderMemoryMiB == 0 || hd.DecoderMemoryMiB == 0 || hd.DecoderMemoryMiB >= hd.EncoderMemoryMiB {
+		t.Errorf("1080p EncoderMemoryMiB/DecoderMemoryMiB = %d/%d, want positive encoder above decoder", hd.EncoderMemoryMiB, hd.DecoderMemoryMiB)
+	}
+	if uhd.EncoderMemoryMiB <= hd.EncoderMemoryMiB || uhd.DecoderMemoryMiB <= hd.DecoderMemoryMiB {
+		t.Errorf("2160p 10-bit EncoderMemoryMiB/DecoderMemoryMiB = %d/%d, want more than 1080p %d/%d",
+			uhd.EncoderMemoryMiB, uhd.DecoderMemoryMiB, hd.EncoderMemoryMiB, hd.DecoderMemoryMiB)
+	}
+}
+
+func TestTemplatePools(t *testing.T) {
+	content, err := os.ReadFile("../../presets/x265.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("x265").Parse(string(content)))
+	params := createSetting(&hevc.EncodeProfile{Width: 3840, Height: 2160, FrameRate: video.FPS25, RateFactor: hevc.HighQuality}, &host.Host{LogicalCores: 32, NUMANodes: 2})
+	buffer := &bytes.Buffer{}
+	if err := tmpl.Execute(buffer, params); err != nil {
+		t.Fatal(err)
+	}
+	if want := `name="pools" value="16,16"`; !strings.Contains(buffer.String(), want) {
+		t.Errorf("template output does not contain %s", want)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "fmt"
+
+// Ratio defines an exact ratio of two integers, used for sample and display aspect ratio.
+type Ratio struct {
+	Num uint32
+	Den uint32
+}
+
+// SquarePixel is the sample aspect ratio of square pixels.
+var SquarePixel = Ratio{Num: 1, Den: 1}
+
+// Return new Ratio of num:den in lowest terms.
+func NewRatio(num, den uint32) Ratio {
+	return Ratio{Num: num, Den: den}.Reduce()
+}
+
+// Return true if the ratio is not defined.
+func (r Ratio) IsZero() bool {
+	return r.Num == 0 || r.Den == 0
+}
+
+// Return the ratio in lowest terms.
+func (r Ratio) Reduce() Ratio {
+	if r.IsZero() {
+		return r
+	}
+	divisor := gcd(uint64(r.Num), uint64(r.Den))
+	return Ratio{Num: uint32(uint64(r.Num) / divisor), Den: uint32(uint64(r.Den) / divisor)}
+}
+
+// Return the ratio as floating point number.
+func (r Ratio) Float64() float64 {
+	if r.IsZero() {
+		return 0
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// Return the ratio in num:den form.
+func (r Ratio) String() string {
+	return fmt.Sprintf("%d:%d", r.Num, r.Den)
+}
+
+// Return the ratio in num:den form.
+func (r Ratio) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Return the display aspect ratio of a picture stored in specified size with specified sample aspect ratio.
+// Zero sample aspect ratio is treated as square pixels.
+func DisplayAspect(width, height uint32, sar Ratio) Ratio {
+	if sar.IsZero() {
+		sar = SquarePixel
+	}
+	num := uint64(width) * uint64(sar.Num)
+	den := uint64(height) * uint64(sar.Den)
+	if num == 0 || den == 0 {
+		return Ratio{}
+	}
+	divisor := gcd(num, den)
+	return Ratio{Num: uint32(num / divisor), Den: uint32(den / divisor)}
+}
+
+// Return the greatest common divisor of a and b.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
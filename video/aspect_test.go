@@ -0,0 +1,76 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestDisplayAspect(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  uint32
+		height uint32
+		sar    Ratio
+		want   Ratio
+	}{
+		{"square pixels", 1920, 1080, SquarePixel, Ratio{16, 9}},
+		{"zero SAR as square pixels", 1920, 1080, Ratio{}, Ratio{16, 9}},
+		{"NTSC DVD", 720, 480, NewRatio(8, 9), Ratio{4, 3}},
+		{"NTSC widescreen DVD", 720, 480, NewRatio(32, 27), Ratio{16, 9}},
+		{"PAL DVD", 720, 576, NewRatio(16, 15), Ratio{4, 3}},
+		{"HDV", 1440, 1080, NewRatio(4, 3), Ratio{16, 9}},
+		{"portrait", 1080, 1920, SquarePixel, Ratio{9, 16}},
+		{"zero size", 0, 1080, SquarePixel, Ratio{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DisplayAspect(tt.width, tt.height, tt.sar); got != tt.want {
+				t.Errorf("DisplayAspect(%d, %d, %v) = %v, want %v", tt.width, tt.height, tt.sar, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRatio(t *testing.T) {
+	tests := []struct {
+		num  uint32
+		den  uint32
+		want Ratio
+	}{
+		{239, 100, Ratio{239, 100}},
+		{64, 45, Ratio{64, 45}},
+		{1920, 1080, Ratio{16, 9}},
+		{0, 1, Ratio{0, 1}},
+		{1, 0, Ratio{1, 0}},
+	}
+	for _, tt := range tests {
+		if got := NewRatio(tt.num, tt.den); got != tt.want {
+			t.Errorf("NewRatio(%d, %d) = %v, want %v", tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestResolutionAspect(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolution Resolution
+		wantSAR    Ratio
+		wantDAR    Ratio
+	}{
+		{"square pixels", Resolution{Width: 1920, Height: 1080}, SquarePixel, Ratio{16, 9}},
+		{"PAL widescreen DVD", Resolution{Width: 720, Height: 576, SampleAspect: NewRatio(64, 45)}, Ratio{64, 45}, Ratio{16, 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resolution.SAR(); got != tt.wantSAR {
+				t.Errorf("SAR() = %v, want %v", got, tt.wantSAR)
+			}
+			if got := tt.resolution.DisplayAspect(); got != tt.wantDAR {
+				t.Errorf("DisplayAspect() = %v, want %v", got, tt.wantDAR)
+			}
+		})
+	}
+}
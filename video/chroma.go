@@ -0,0 +1,75 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "fmt"
+
+// ChromaFormat defines the chroma subsampling of YUV video.
+type ChromaFormat uint8
+
+const (
+	Chroma420 ChromaFormat = iota
+	Chroma422
+	Chroma444
+)
+
+// Return the subsampling without separators, e.g. "422" for 4:2:2.
+func (c ChromaFormat) Label() string {
+	switch c {
+	case Chroma422:
+		return "422"
+	case Chroma444:
+		return "444"
+	}
+	return "420"
+}
+
+// Return the number of luma and chroma samples per pixel, e.g. 1.5 for 4:2:0.
+func (c ChromaFormat) SamplesPerPixel() float64 {
+	switch c {
+	case Chroma422:
+		return 2
+	case Chroma444:
+		return 3
+	}
+	return 1.5
+}
+
+// Return the color space name used by x264, x265 and Hybrid, e.g. "i422" for 4:2:2.
+func (c ChromaFormat) ColorSpace() string {
+	return "i" + c.Label()
+}
+
+// Return the FFmpeg pixel format of specified bit depth and chroma format, e.g. "yuv422p10le".
+func PixelFormat(bitDepth uint8, chromaFormat ChromaFormat) string {
+	if bitDepth <= 8 {
+		return fmt.Sprintf("yuv%sp", chromaFormat.Label())
+	}
+	return fmt.Sprintf("yuv%sp%dle", chromaFormat.Label(), bitDepth)
+}
+
+// Return the label of specified bit depth and chroma format used in profile name, e.g. "10b422",
+// or "10b" for 4:2:0. Return empty for 8-bit 4:2:0, which is the default.
+func FormatLabel(bitDepth uint8, chromaFormat ChromaFormat) string {
+	if bitDepth <= 8 && chromaFormat == Chroma420 {
+		return ""
+	}
+	if bitDepth == 0 {
+		bitDepth = 8
+	}
+	label := fmt.Sprintf("%db", bitDepth)
+	if chromaFormat != Chroma420 {
+		label += chromaFormat.Label()
+	}
+	return label
+}
+
+// Return the bitrate in kbps of uncompressed video of specified size, frame rate and format.
+func RawBitRate(width, height uint32, frameRate FrameRate, bitDepth uint8, chromaFormat ChromaFormat) uint64 {
+	samples := float64(width) * float64(height) * frameRate.Float64() * chromaFormat.SamplesPerPixel()
+	return uint64(samples * float64(bitDepth) / 1000)
+}
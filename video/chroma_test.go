@@ -0,0 +1,79 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestChromaFormat(t *testing.T) {
+	tests := []struct {
+		chromaFormat   ChromaFormat
+		wantLabel      string
+		wantColorSpace string
+		wantSamples    float64
+	}{
+		{Chroma420, "420", "i420", 1.5},
+		{Chroma422, "422", "i422", 2},
+		{Chroma444, "444", "i444", 3},
+	}
+	for _, tt := range tests {
+		if got := tt.chromaFormat.Label(); got != tt.wantLabel {
+			t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+		}
+		if got := tt.chromaFormat.ColorSpace(); got != tt.wantColorSpace {
+			t.Errorf("ColorSpace() = %q, want %q", got, tt.wantColorSpace)
+		}
+		if got := tt.chromaFormat.SamplesPerPixel(); got != tt.wantSamples {
+			t.Errorf("SamplesPerPixel() = %v, want %v", got, tt.wantSamples)
+		}
+	}
+}
+
+func TestPixelFormatAndLabel(t *testing.T) {
+	tests := []struct {
+		bitDepth        uint8
+		chromaFormat    ChromaFormat
+		wantPixelFormat string
+		wantLabel       string
+	}{
+		{8, Chroma420, "yuv420p", ""},
+		{0, Chroma420, "yuv420p", ""},
+		{10, Chroma420, "yuv420p10le", "10b"},
+		{8, Chroma422, "yuv422p", "8b422"},
+		{0, Chroma444, "yuv444p", "8b444"},
+		{10, Chroma422, "yuv422p10le", "10b422"},
+		{12, Chroma444, "yuv444p12le", "12b444"},
+	}
+	for _, tt := range tests {
+		if got := PixelFormat(tt.bitDepth, tt.chromaFormat); got != tt.wantPixelFormat {
+			t.Errorf("PixelFormat(%d, %s) = %q, want %q", tt.bitDepth, tt.chromaFormat.Label(), got, tt.wantPixelFormat)
+		}
+		if got := FormatLabel(tt.bitDepth, tt.chromaFormat); got != tt.wantLabel {
+			t.Errorf("FormatLabel(%d, %s) = %q, want %q", tt.bitDepth, tt.chromaFormat.Label(), got, tt.wantLabel)
+		}
+	}
+}
+
+func TestRawBitRate(t *testing.T) {
+	tests := []struct {
+		width        uint32
+		height       uint32
+		frameRate    FrameRate
+		bitDepth     uint8
+		chromaFormat ChromaFormat
+		want         uint64
+	}{
+		{1920, 1080, FPS25, 8, Chroma420, 622080},
+		{1920, 1080, FPS25, 10, Chroma422, 1036800},
+		{1920, 1080, FPS25, 8, Chroma444, 1244160},
+		{1920, 1080, FPS23976, 8, Chroma420, 596600},
+	}
+	for _, tt := range tests {
+		if got := RawBitRate(tt.width, tt.height, tt.frameRate, tt.bitDepth, tt.chromaFormat); got != tt.want {
+			t.Errorf("RawBitRate(%d, %d, %v, %d, %s) = %d, want %d", tt.width, tt.height, tt.frameRate, tt.bitDepth, tt.chromaFormat.Label(), got, tt.want)
+		}
+	}
+}
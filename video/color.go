@@ -0,0 +1,48 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+// ColorDescription describes how sample values map to colors, as signaled in VUI.
+// Primaries, Transfer and Matrix are in x264/x265 notation, Range is "limited" or "full".
+// ChromaLocation is the chroma sample location type of 4:2:0 video, 0 is left and 2 is top-left.
+type ColorDescription struct {
+	Primaries      string
+	Transfer       string
+	Matrix         string
+	Range          string
+	ChromaLocation uint8
+}
+
+// Common color descriptions of SD, HD and UHD video.
+var (
+	BT601NTSC = ColorDescription{Primaries: "smpte170m", Transfer: "smpte170m", Matrix: "smpte170m", Range: "limited", ChromaLocation: 0}
+	BT601PAL  = ColorDescription{Primaries: "bt470bg", Transfer: "bt470bg", Matrix: "bt470bg", Range: "limited", ChromaLocation: 0}
+	BT709     = ColorDescription{Primaries: "bt709", Transfer: "bt709", Matrix: "bt709", Range: "limited", ChromaLocation: 0}
+	BT2020    = ColorDescription{Primaries: "bt2020", Transfer: "bt2020-10", Matrix: "bt2020nc", Range: "limited", ChromaLocation: 2}
+	BT2020PQ  = ColorDescription{Primaries: "bt2020", Transfer: "smpte2084", Matrix: "bt2020nc", Range: "limited", ChromaLocation: 2}
+	BT2020HLG = ColorDescription{Primaries: "bt2020", Transfer: "arib-std-b67", Matrix: "bt2020nc", Range: "limited", ChromaLocation: 2}
+)
+
+// Return true if no color description is specified.
+func (c ColorDescription) IsZero() bool {
+	return c == ColorDescription{}
+}
+
+// Return the default color description of SDR video of specified size.
+// SD rasters, 704 or 720 wide and 480, 486 or 576 high, use BT.601 with 525-line (NTSC)
+// or 625-line (PAL) colors, and all other video uses BT.709. UHD HDR should use BT2020PQ or BT2020HLG instead.
+func DefaultColor(width, height uint32) ColorDescription {
+	if width == 704 || width == 720 {
+		switch height {
+		case 480, 486:
+			return BT601NTSC
+		case 576:
+			return BT601PAL
+		}
+	}
+	return BT709
+}
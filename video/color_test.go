@@ -0,0 +1,57 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestDefaultColor(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  uint32
+		height uint32
+		want   ColorDescription
+	}{
+		{"NTSC DVD", 720, 480, BT601NTSC},
+		{"NTSC D1", 720, 486, BT601NTSC},
+		{"PAL DVD", 720, 576, BT601PAL},
+		{"NTSC 704", 704, 480, BT601NTSC},
+		{"PAL 704", 704, 576, BT601PAL},
+		{"NTSC DVD portrait", 480, 720, BT709},
+		{"VGA", 640, 480, BT709},
+		{"qHD", 960, 540, BT709},
+		{"PAL widescreen square pixels", 1024, 576, BT709},
+		{"720p", 1280, 720, BT709},
+		{"1080p", 1920, 1080, BT709},
+		{"2160p", 3840, 2160, BT709},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultColor(tt.width, tt.height); got != tt.want {
+				t.Errorf("DefaultColor(%d, %d) = %+v, want %+v", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolutionColorDescription(t *testing.T) {
+	full := ColorDescription{Primaries: "bt709", Transfer: "bt709", Matrix: "bt709", Range: "full"}
+	tests := []struct {
+		name       string
+		resolution Resolution
+		want       ColorDescription
+	}{
+		{"default", Resolution{Width: 1920, Height: 1080}, BT709},
+		{"custom", Resolution{Width: 720, Height: 576, Color: full}, full},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resolution.ColorDescription(); got != tt.want {
+				t.Errorf("ColorDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
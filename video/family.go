@@ -0,0 +1,77 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+// Alignment defines the multiple in pixels that a picture dimension is rounded to.
+type Alignment uint16
+
+const (
+	Mod2  Alignment = 2
+	Mod8  Alignment = 8
+	Mod16 Alignment = 16
+)
+
+// Return value rounded up to the nearest multiple of alignment.
+func (a Alignment) Pad(value uint32) uint32 {
+	if a == 0 {
+		return value
+	}
+	return uint32((uint64(value) + uint64(a) - 1) / uint64(a) * uint64(a))
+}
+
+// Return value rounded to the nearest multiple of alignment, but not less than alignment.
+func (a Alignment) Round(value uint32) uint32 {
+	if a == 0 {
+		return value
+	}
+	rounded := uint32((uint64(value) + uint64(a)/2) / uint64(a) * uint64(a))
+	if rounded == 0 {
+		return uint32(a)
+	}
+	return rounded
+}
+
+// Common display aspect ratios of cropped and cinemascope films.
+var (
+	Aspect239 = NewRatio(239, 100)
+	Aspect235 = NewRatio(235, 100)
+	Aspect200 = NewRatio(2, 1)
+	Aspect185 = NewRatio(185, 100)
+	Aspect169 = NewRatio(16, 9)
+	Aspect43  = NewRatio(4, 3)
+)
+
+// Return the height of a square pixel picture of specified width and display aspect ratio,
+// rounded to the nearest multiple of alignment.
+func AlignedHeight(width uint32, aspect Ratio, alignment Alignment) uint32 {
+	if width == 0 || aspect.IsZero() {
+		return 0
+	}
+	if alignment == 0 {
+		alignment = Mod2
+	}
+	// height = width / aspect, rounded to nearest multiple of alignment using integer math only
+	divisor := uint64(aspect.Num) * uint64(alignment)
+	units := (uint64(width)*uint64(aspect.Den)*2 + divisor) / (divisor * 2)
+	return uint32(units * uint64(alignment))
+}
+
+// Return a family of resolutions sharing the same width, one for each display aspect ratio,
+// with heights aligned to specified alignment. Duplicated heights are returned once.
+func ResolutionsByAspect(width uint32, aspects []Ratio, alignment Alignment) []*Resolution {
+	resolutions := []*Resolution{}
+	heights := map[uint32]bool{}
+	for _, aspect := range aspects {
+		height := AlignedHeight(width, aspect, alignment)
+		if height == 0 || heights[height] {
+			continue
+		}
+		heights[height] = true
+		resolutions = append(resolutions, &Resolution{Width: width, Height: height})
+	}
+	return resolutions
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestAlignedHeight(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     uint32
+		aspect    Ratio
+		alignment Alignment
+		want      uint32
+	}{
+		{"1080p 2.39 mod 8", 1920, Aspect239, Mod8, 800},
+		{"1080p 2.35 mod 8", 1920, Aspect235, Mod8, 816},
+		{"1080p 1.85 mod 8", 1920, Aspect185, Mod8, 1040},
+		{"1080p 2.39 mod 16", 1920, Aspect239, Mod16, 800},
+		{"1080p 1.85 mod 16", 1920, Aspect185, Mod16, 1040},
+		{"2160p 2.39 mod 8", 3840, Aspect239, Mod8, 1608},
+		{"2160p 2.39 mod 16", 3840, Aspect239, Mod16, 1600},
+		{"16:9", 1920, Aspect169, Mod8, 1080},
+		{"16:9 mod 16", 1920, Aspect169, Mod16, 1088},
+		{"default alignment", 1920, Aspect43, 0, 1440},
+		{"zero width", 0, Aspect169, Mod8, 0},
+		{"zero aspect", 1920, Ratio{}, Mod8, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AlignedHeight(tt.width, tt.aspect, tt.alignment); got != tt.want {
+				t.Errorf("AlignedHeight(%d, %v, %d) = %d, want %d", tt.width, tt.aspect, tt.alignment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolutionsByAspect(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     uint32
+		aspects   []Ratio
+		alignment Alignment
+		want      []uint32
+	}{
+		{"cropped films mod 8", 1920, []Ratio{Aspect239, Aspect235, Aspect200, Aspect185}, Mod8, []uint32{800, 816, 960, 1040}},
+		{"duplicated heights", 640, []Ratio{Aspect239, Aspect235}, Mod16, []uint32{272}},
+		{"zero aspect skipped", 1920, []Ratio{{}, Aspect169}, Mod8, []uint32{1080}},
+		{"no aspect", 1920, nil, Mod8, []uint32{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolutions := ResolutionsByAspect(tt.width, tt.aspects, tt.alignment)
+			heights := make([]uint32, len(resolutions))
+			for i, resolution := range resolutions {
+				if resolution.Width != tt.width {
+					t.Errorf("Width = %d, want %d", resolution.Width, tt.width)
+				}
+				heights[i] = resolution.Height
+			}
+			if len(heights) != len(tt.want) {
+				t.Fatalf("heights = %v, want %v", heights, tt.want)
+			}
+			for i := range heights {
+				if heights[i] != tt.want[i] {
+					t.Errorf("heights = %v, want %v", heights, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestAlignmentPad(t *testing.T) {
+	tests := []struct {
+		alignment Alignment
+		value     uint32
+		want      uint32
+	}{
+		{Mod16, 1080, 1088},
+		{Mod16, 1072, 1072},
+		{Mod16, 1079, 1088},
+		{Mod8, 1080, 1080},
+		{Mod8, 1606, 1608},
+		{Mod2, 1, 2},
+		{Mod2, 0, 0},
+		{0, 1081, 1081},
+	}
+	for _, tt := range tests {
+		if got := tt.alignment.Pad(tt.value); got != tt.want {
+			t.Errorf("Alignment(%d).Pad(%d) = %d, want %d", tt.alignment, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAlignmentRound(t *testing.T) {
+	tests := []struct {
+		alignment Alignment
+		value     uint32
+		want      uint32
+	}{
+		{Mod2, 540, 540},
+		{Mod2, 683, 684},
+		{Mod2, 243, 244},
+		{Mod2, 1, 2},
+		{Mod2, 0, 2},
+		{Mod16, 1080, 1088},
+		{Mod16, 1075, 1072},
+		{0, 683, 683},
+	}
+	for _, tt := range tests {
+		if got := tt.alignment.Round(tt.value); got != tt.want {
+			t.Errorf("Alignment(%d).Round(%d) = %d, want %d", tt.alignment, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FrameRate defines an exact frame rate as a rational number of frames per second.
+type FrameRate struct {
+	Num uint32
+	Den uint32
+}
+
+// Common frame rates, including NTSC rates with 1001 denominator.
+var (
+	FPS23976  = FrameRate{Num: 24000, Den: 1001}
+	FPS24     = FrameRate{Num: 24, Den: 1}
+	FPS25     = FrameRate{Num: 25, Den: 1}
+	FPS29970  = FrameRate{Num: 30000, Den: 1001}
+	FPS30     = FrameRate{Num: 30, Den: 1}
+	FPS50     = FrameRate{Num: 50, Den: 1}
+	FPS59940  = FrameRate{Num: 60000, Den: 1001}
+	FPS60     = FrameRate{Num: 60, Den: 1}
+	FPS100    = FrameRate{Num: 100, Den: 1}
+	FPS119880 = FrameRate{Num: 120000, Den: 1001}
+	FPS120    = FrameRate{Num: 120, Den: 1}
+	FPS240    = FrameRate{Num: 240, Den: 1}
+)
+
+// Return new FrameRate of num/den frames per second.
+func NewFrameRate(num, den uint32) FrameRate {
+	return FrameRate{Num: num, Den: den}
+}
+
+// Return true if the frame rate is not defined.
+func (f FrameRate) IsZero() bool {
+	return f.Num == 0 || f.Den == 0
+}
+
+// Return the frame rate as floating point number, for heuristics only.
+func (f FrameRate) Float64() float64 {
+	if f.IsZero() {
+		return 0
+	}
+	return float64(f.Num) / float64(f.Den)
+}
+
+// Return the number of frames in specified duration, rounded up.
+func (f FrameRate) Frames(seconds uint32) uint32 {
+	if f.IsZero() {
+		return 0
+	}
+	return uint32((uint64(seconds)*uint64(f.Num) + uint64(f.Den) - 1) / uint64(f.Den))
+}
+
+// Return the shortest decimal form of the frame rate with at most 3 decimals, e.g. 25 or 23.976.
+func (f FrameRate) String() string {
+	return strings.TrimSuffix(strings.TrimRight(f.decimal(), "0"), ".")
+}
+
+// Return the decimal form of the frame rate used in profile names,
+// with 2 to 3 decimals, e.g. 25.00, 29.97 or 23.976.
+func (f FrameRate) Label() string {
+	label := f.String()
+	dot := strings.IndexByte(label, '.')
+	if dot < 0 {
+		return label + ".00"
+	}
+	if len(label)-dot-1 < 2 {
+		return label + "0"
+	}
+	return label
+}
+
+// Return the frame rate in num/den form, or num only if den is 1.
+func (f FrameRate) MarshalText() ([]byte, error) {
+	if f.Den == 1 {
+		return []byte(strconv.FormatUint(uint64(f.Num), 10)), nil
+	}
+	return []byte(fmt.Sprintf("%d/%d", f.Num, f.Den)), nil
+}
+
+// Parse the frame rate in num/den or num form, both must be positive.
+func (f *FrameRate) UnmarshalText(text []byte) error {
+	numText, denText, found := strings.Cut(string(text), "/")
+	num, err := strconv.ParseUint(numText, 10, 32)
+	if err != nil || num == 0 {
+		return fmt.Errorf("invalid frame rate %q", text)
+	}
+	den := uint64(1)
+	if found {
+		den, err = strconv.ParseUint(denText, 10, 32)
+		if err != nil || den == 0 {
+			return fmt.Errorf("invalid frame rate %q", text)
+		}
+	}
+	f.Num = uint32(num)
+	f.Den = uint32(den)
+	return nil
+}
+
+// Return the frame rate rounded to 3 decimals.
+func (f FrameRate) decimal() string {
+	return strconv.FormatFloat(math.Round(f.Float64()*1000)/1000, 'f', 3, 64)
+}
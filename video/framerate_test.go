@@ -0,0 +1,93 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestFrameRateUnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    FrameRate
+		wantErr bool
+	}{
+		{"25", FPS25, false},
+		{"24000/1001", FPS23976, false},
+		{"30/1", FPS30, false},
+		{"0", FrameRate{}, true},
+		{"0/1001", FrameRate{}, true},
+		{"25/0", FrameRate{}, true},
+		{"-25", FrameRate{}, true},
+		{"25.0", FrameRate{}, true},
+		{"25/", FrameRate{}, true},
+		{"/1001", FrameRate{}, true},
+		{"", FrameRate{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := FrameRate{}
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalText(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameRateText(t *testing.T) {
+	tests := []struct {
+		frameRate  FrameRate
+		wantText   string
+		wantString string
+		wantLabel  string
+	}{
+		{FPS25, "25", "25", "25.00"},
+		{FPS23976, "24000/1001", "23.976", "23.976"},
+		{FPS29970, "30000/1001", "29.97", "29.97"},
+		{FPS119880, "120000/1001", "119.88", "119.88"},
+		{FPS240, "240", "240", "240.00"},
+		{NewFrameRate(50, 2), "50/2", "25", "25.00"},
+	}
+	for _, tt := range tests {
+		text, err := tt.frameRate.MarshalText()
+		if err != nil || string(text) != tt.wantText {
+			t.Errorf("MarshalText(%v) = %q, %v, want %q", tt.frameRate, text, err, tt.wantText)
+		}
+		if got := tt.frameRate.String(); got != tt.wantString {
+			t.Errorf("String(%v) = %q, want %q", tt.frameRate, got, tt.wantString)
+		}
+		if got := tt.frameRate.Label(); got != tt.wantLabel {
+			t.Errorf("Label(%v) = %q, want %q", tt.frameRate, got, tt.wantLabel)
+		}
+		parsed := FrameRate{}
+		if err := parsed.UnmarshalText(text); err != nil || parsed != tt.frameRate {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, parsed, err, tt.frameRate)
+		}
+	}
+}
+
+func TestFrameRateFrames(t *testing.T) {
+	tests := []struct {
+		frameRate FrameRate
+		seconds   uint32
+		want      uint32
+	}{
+		{FPS25, 2, 50},
+		{FPS23976, 2, 48},
+		{FPS29970, 10, 300},
+		{FPS59940, 1, 60},
+		{FrameRate{}, 2, 0},
+		{FrameRate{Num: 25}, 2, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.frameRate.Frames(tt.seconds); got != tt.want {
+			t.Errorf("Frames(%v, %d) = %d, want %d", tt.frameRate, tt.seconds, got, tt.want)
+		}
+	}
+}
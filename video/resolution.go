@@ -6,9 +6,88 @@
 
 package video
 
-// Resolution defines a video resolution with width, height, and frame rate.
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resolution defines a video resolution with storage size, sample aspect ratio, frame rate and scan type.
+// Width and Height are the stored size in pixels, SampleAspect is the shape of a pixel
+// and is treated as square pixels when zero.
+// FrameRate is always the number of frames per second, an interlaced frame contains 2 fields.
+// FieldOrder is only meaningful for interlaced scan types.
+// Color is the color description, which is DefaultColor of the size when zero.
 type Resolution struct {
-	Width     uint16
-	Height    uint16
-	FrameRate float64
+	Width        uint32
+	Height       uint32
+	SampleAspect Ratio
+	FrameRate    FrameRate
+	ScanType     ScanType
+	FieldOrder   FieldOrder
+	Color        ColorDescription
+}
+
+// Return the sample aspect ratio, which is square pixels if not defined.
+func (r *Resolution) SAR() Ratio {
+	if r.SampleAspect.IsZero() {
+		return SquarePixel
+	}
+	return r.SampleAspect
+}
+
+// Return the color description, which is the default of the size if not defined.
+func (r *Resolution) ColorDescription() ColorDescription {
+	if r.Color.IsZero() {
+		return DefaultColor(r.Width, r.Height)
+	}
+	return r.Color
+}
+
+// Return a copy of the resolution scaled by ratio, with dimensions rounded to the nearest multiple of alignment.
+// Sample aspect ratio, frame rate and scan type are kept, so is the display aspect ratio if alignment allows.
+func (r *Resolution) Scale(ratio Ratio, alignment Alignment) *Resolution {
+	scaled := *r
+	if ratio.IsZero() {
+		return &scaled
+	}
+	scaled.Width = alignment.Round(uint32(uint64(r.Width) * uint64(ratio.Num) / uint64(ratio.Den)))
+	scaled.Height = alignment.Round(uint32(uint64(r.Height) * uint64(ratio.Num) / uint64(ratio.Den)))
+	return &scaled
+}
+
+// Return the display aspect ratio, e.g. 16:9 for 720x480 with 32:27 sample aspect ratio.
+func (r *Resolution) DisplayAspect() Ratio {
+	return DisplayAspect(r.Width, r.Height, r.SampleAspect)
+}
+
+// Return the longer dimension of the resolution, regardless of orientation.
+func (r *Resolution) LongEdge() uint32 {
+	return LongEdge(r.Width, r.Height)
+}
+
+// Return the longer dimension of specified size, so landscape and portrait video
+// of the same pixel count are treated alike.
+func LongEdge(width, height uint32) uint32 {
+	if height > width {
+		return height
+	}
+	return width
+}
+
+// Parse the size in WIDTHxHEIGHT form, e.g. 1920x1080.
+func ParseSize(text string) (width, height uint32, err error) {
+	widthText, heightText, found := strings.Cut(strings.ToLower(text), "x")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT", text)
+	}
+	w, err := strconv.ParseUint(widthText, 10, 32)
+	if err != nil || w == 0 {
+		return 0, 0, fmt.Errorf("invalid width of size %q", text)
+	}
+	h, err := strconv.ParseUint(heightText, 10, 32)
+	if err != nil || h == 0 {
+		return 0, 0, fmt.Errorf("invalid height of size %q", text)
+	}
+	return uint32(w), uint32(h), nil
 }
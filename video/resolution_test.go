@@ -0,0 +1,95 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestLongEdge(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  uint32
+		height uint32
+		want   uint32
+	}{
+		{"landscape", 1920, 1080, 1920},
+		{"portrait", 1080, 1920, 1920},
+		{"square", 1080, 1080, 1080},
+		{"8K", 7680, 4320, 7680},
+		{"8K portrait", 4320, 7680, 7680},
+		{"zero", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LongEdge(tt.width, tt.height); got != tt.want {
+				t.Errorf("LongEdge(%d, %d) = %d, want %d", tt.width, tt.height, got, tt.want)
+			}
+			resolution := &Resolution{Width: tt.width, Height: tt.height}
+			if got := resolution.LongEdge(); got != tt.want {
+				t.Errorf("Resolution.LongEdge() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolutionScale(t *testing.T) {
+	ntsc := &Resolution{Width: 720, Height: 480, SampleAspect: NewRatio(32, 27), FrameRate: FPS29970, ScanType: Interlaced, FieldOrder: BottomFieldFirst}
+	tests := []struct {
+		name       string
+		resolution *Resolution
+		ratio      Ratio
+		want       *Resolution
+	}{
+		{"1080p half", &Resolution{Width: 1920, Height: 1080, FrameRate: FPS25}, NewRatio(1, 2), &Resolution{Width: 960, Height: 540, FrameRate: FPS25}},
+		{"1080p quarter", &Resolution{Width: 1920, Height: 1080, FrameRate: FPS25}, NewRatio(1, 4), &Resolution{Width: 480, Height: 270, FrameRate: FPS25}},
+		{"odd width after halving", &Resolution{Width: 1366, Height: 768}, NewRatio(1, 2), &Resolution{Width: 684, Height: 384}},
+		{"odd height after halving", &Resolution{Width: 720, Height: 486}, NewRatio(1, 2), &Resolution{Width: 360, Height: 244}},
+		{"odd width after quartering", &Resolution{Width: 1998, Height: 1080}, NewRatio(1, 4), &Resolution{Width: 500, Height: 270}},
+		{"keeps sample aspect and scan", ntsc, NewRatio(1, 2), &Resolution{Width: 360, Height: 240, SampleAspect: NewRatio(32, 27), FrameRate: FPS29970, ScanType: Interlaced, FieldOrder: BottomFieldFirst}},
+		{"zero ratio", &Resolution{Width: 1920, Height: 1080}, Ratio{}, &Resolution{Width: 1920, Height: 1080}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resolution.Scale(tt.ratio, Mod2); *got != *tt.want {
+				t.Errorf("Scale() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+	if got := ntsc.Scale(NewRatio(1, 2), Mod2); got == ntsc || ntsc.Width != 720 {
+		t.Errorf("Scale() must return a copy")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		text       string
+		wantWidth  uint32
+		wantHeight uint32
+		wantErr    bool
+	}{
+		{"1920x1080", 1920, 1080, false},
+		{"3840X2160", 3840, 2160, false},
+		{"1080x1920", 1080, 1920, false},
+		{"1920", 0, 0, true},
+		{"0x1080", 0, 0, true},
+		{"1920x0", 0, 0, true},
+		{"-1920x1080", 0, 0, true},
+		{"1920x1080x3", 0, 0, true},
+		{"x", 0, 0, true},
+		{"99999999999x1080", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			width, height, err := ParseSize(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("ParseSize(%q) = %dx%d, want %dx%d", tt.text, width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+// ScanType defines how pictures of a video are scanned and coded.
+type ScanType uint8
+
+const (
+	// Progressive frames, coded as frames.
+	Progressive ScanType = iota
+	// Interlaced frames, each frame contains 2 fields captured at different time.
+	Interlaced
+	// Progressive frames signaled as interlaced, also known as PsF.
+	FakeInterlaced
+	// Progressive frames displayed at higher field rate with soft 3:2 pulldown.
+	Telecined
+)
+
+// FieldOrder defines which field of an interlaced frame is displayed first.
+type FieldOrder uint8
+
+const (
+	TopFieldFirst FieldOrder = iota
+	BottomFieldFirst
+)
+
+// Return true if the scan type requires field coding tools of the encoder.
+func (s ScanType) IsFieldCoded() bool {
+	return s == Interlaced || s == FakeInterlaced
+}
+
+// Return the label of the scan type used in profile names, e.g. i for 1080i.
+// Progressive scan has empty label.
+func (s ScanType) Label(order FieldOrder) string {
+	switch s {
+	case Interlaced:
+		if order == BottomFieldFirst {
+			return "ib"
+		}
+		return "i"
+	case FakeInterlaced:
+		return "psf"
+	case Telecined:
+		return "tc"
+	}
+	return ""
+}
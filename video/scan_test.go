@@ -0,0 +1,36 @@
+// Copyright (C) 2025 Nguyen Nhat Tung
+//
+// Hybrid Profile Generator is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package video
+
+import "testing"
+
+func TestScanType(t *testing.T) {
+	tests := []struct {
+		name           string
+		scanType       ScanType
+		fieldOrder     FieldOrder
+		wantLabel      string
+		wantFieldCoded bool
+	}{
+		{"progressive", Progressive, TopFieldFirst, "", false},
+		{"progressive ignores field order", Progressive, BottomFieldFirst, "", false},
+		{"interlaced tff", Interlaced, TopFieldFirst, "i", true},
+		{"interlaced bff", Interlaced, BottomFieldFirst, "ib", true},
+		{"fake interlaced", FakeInterlaced, TopFieldFirst, "psf", true},
+		{"telecined", Telecined, TopFieldFirst, "tc", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scanType.Label(tt.fieldOrder); got != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+			}
+			if got := tt.scanType.IsFieldCoded(); got != tt.wantFieldCoded {
+				t.Errorf("IsFieldCoded() = %v, want %v", got, tt.wantFieldCoded)
+			}
+		})
+	}
+}